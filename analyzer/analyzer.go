@@ -0,0 +1,265 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/build"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/sirkon/cerrful/internal/cerrful"
+	"github.com/sirkon/cerrful/internal/cir"
+	"github.com/sirkon/cerrful/internal/config"
+	"github.com/sirkon/cerrful/internal/tracing"
+)
+
+// Analyzer checks error handling discipline against CER-series rules.
+var Analyzer = &analysis.Analyzer{
+	Name: "cerrful",
+	Doc:  "checks that errors are never dropped, properly annotated and either logged or returned",
+	URL:  "https://github.com/sirkon/cerrful",
+	Run:  run,
+
+	FactTypes: []analysis.Fact{new(tracing.Summary), new(tracing.PackageAnnotations)},
+}
+
+var (
+	configPath string
+	debugCIR   bool
+)
+
+func init() {
+	Analyzer.Flags.StringVar(
+		&configPath,
+		"config",
+		"",
+		"path to configuration file, the nearest "+config.FileName+" found from package directory upwards through the module root and its parents is used if not set",
+	)
+	Analyzer.Flags.BoolVar(
+		&debugCIR,
+		"debug-cir",
+		false,
+		"translate analyzed files into CIR and report violations of its invariants",
+	)
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	if len(pass.Files) == 0 || isStd(pass) {
+		// The standard library is known from built-in knowledge, it is only visited
+		// as a dependency for its facts.
+		return nil, nil
+	}
+
+	cfg, err := loadConfig(pass)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports tracing.ReportEngine
+
+	var module string
+	if pass.Module != nil {
+		module = pass.Module.Path
+	}
+
+	engine := tracing.NewScrapEngine(reports.Phase(tracing.ReportScrap))
+	cfg.Apply(engine)
+	engine.SetForbiddenTerms(cfg.ForbiddenTerms.Of(module, pass.Pkg.Path()))
+
+	ctx := tracing.NewContext()
+	for _, file := range pass.Files {
+		engine.Scrap(ctx, pass, file)
+	}
+	env := tracing.Environment{
+		Summaries: func(fn *types.Func) *tracing.Summary {
+			var res tracing.Summary
+			if !pass.ImportObjectFact(fn, &res) {
+				return nil
+			}
+			return &res
+		},
+		Layer: func(pkg *types.Package) string {
+			return cfg.Layer(module, pkg.Path())
+		},
+		Sentinel: engine.IsIgnoredError,
+	}
+	checkAnnotations(pass, cfg, module, engine.Annotations(), reports.Phase(tracing.ReportState))
+
+	fns := tracing.BuildSSA(pass)
+	summaries := tracing.InterpretPackage(fns, ctx, &reports, env)
+	for _, fn := range fns {
+		// Other packages can only call exported functions and methods.
+		if obj, ok := fn.Object().(*types.Func); ok && obj.Exported() && summaries[fn] != nil {
+			pass.ExportObjectFact(obj, summaries[fn])
+		}
+	}
+
+	for _, rep := range reports.Reports() {
+		pos := position(pass, rep.Pos)
+		if !pos.IsValid() {
+			continue
+		}
+
+		var related []analysis.RelatedInformation
+		for _, rel := range rep.Related {
+			if relPos := position(pass, rel.Pos); relPos.IsValid() {
+				related = append(related, analysis.RelatedInformation{
+					Pos:     relPos,
+					Message: rel.Message,
+				})
+			}
+		}
+
+		pass.Report(analysis.Diagnostic{
+			Pos:            pos,
+			Category:       rep.RuleCode.String(),
+			Message:        fmt.Sprintf("%s — %s", rep.RuleCode, rep.Message),
+			Related:        related,
+			SuggestedFixes: suggestedFixes(pass, rep.Fixes),
+		})
+	}
+
+	if debugCIR {
+		validateCIR(pass, cfg)
+	}
+
+	return nil, nil
+}
+
+// checkAnnotations checks annotation messages of the package are unique within the configured
+// scope. Annotations are exported as a package fact for the module scope, only packages the
+// package depends on are seen this way.
+func checkAnnotations(
+	pass *analysis.Pass,
+	cfg *config.Config,
+	module string,
+	annotations []tracing.Annotation,
+	r *tracing.ReporterPhase,
+) {
+	var foreign []tracing.Annotation
+	if cfg.UniquenessScope == config.UniquenessScopeModule && module != "" {
+		for _, fact := range pass.AllPackageFacts() {
+			path := fact.Package.Path()
+			if fact.Package == pass.Pkg || path != module && !strings.HasPrefix(path, module+"/") {
+				continue
+			}
+
+			if v, ok := fact.Fact.(*tracing.PackageAnnotations); ok {
+				foreign = append(foreign, v.List...)
+			}
+		}
+
+		if len(annotations) > 0 {
+			pass.ExportPackageFact(&tracing.PackageAnnotations{List: annotations})
+		}
+	}
+
+	tracing.CheckAnnotations(r, annotations, foreign, cfg.UniquenessScope == config.UniquenessScopeFunction)
+}
+
+// validateCIR reports violations of CIR invariants in files of the pass. These are
+// translator bugs rather than issues of the code analyzed.
+func validateCIR(pass *analysis.Pass, cfg *config.Config) {
+	for _, file := range pass.Files {
+		prog := cerrful.Translate(pass, cfg, file)
+		for _, v := range cir.Validate(prog.Functions...) {
+			pos := file.Pos()
+			if v.Node != nil && v.Node.Pos().IsValid() {
+				pos = v.Node.Pos()
+			}
+
+			pass.Reportf(pos, "CIR invariant violation in %s", v)
+		}
+	}
+}
+
+// suggestedFixes converts fixes of a report. Fixes having edits out of files of
+// the pass are dropped.
+func suggestedFixes(pass *analysis.Pass, fixes []tracing.ReportFix) []analysis.SuggestedFix {
+	var res []analysis.SuggestedFix
+	for _, fix := range fixes {
+		sf := analysis.SuggestedFix{Message: fix.Message}
+		for _, edit := range fix.Edits {
+			pos, end := position(pass, edit.Pos), position(pass, edit.End)
+			if !pos.IsValid() || !end.IsValid() {
+				sf.TextEdits = nil
+				break
+			}
+
+			sf.TextEdits = append(sf.TextEdits, analysis.TextEdit{
+				Pos:     pos,
+				End:     end,
+				NewText: []byte(edit.NewText),
+			})
+		}
+
+		if len(sf.TextEdits) > 0 {
+			res = append(res, sf)
+		}
+	}
+
+	return res
+}
+
+// position maps a reported position back to the file set of the pass.
+func position(pass *analysis.Pass, p token.Position) token.Pos {
+	for _, file := range pass.Files {
+		tf := pass.Fset.File(file.Pos())
+		if tf == nil || tf.Name() != p.Filename {
+			continue
+		}
+
+		if p.Line < 1 || p.Line > tf.LineCount() {
+			return token.NoPos
+		}
+
+		return tf.LineStart(p.Line) + token.Pos(max(p.Column-1, 0))
+	}
+
+	return token.NoPos
+}
+
+// isStd checks if the package of the pass belongs to the standard library.
+func isStd(pass *analysis.Pass) bool {
+	root := filepath.Join(build.Default.GOROOT, "src") + string(filepath.Separator)
+	name := pass.Fset.File(pass.Files[0].Pos()).Name()
+	return strings.HasPrefix(name, root)
+}
+
+// configs caches loaded configurations by their paths.
+var configs sync.Map
+
+type configEntry struct {
+	once sync.Once
+	cfg  *config.Config
+	err  error
+}
+
+// loadConfig loads configuration for the package of the pass. An empty one
+// is used when there is no configuration file.
+func loadConfig(pass *analysis.Pass) (*config.Config, error) {
+	path := configPath
+	if path == "" {
+		dir := filepath.Dir(pass.Fset.File(pass.Files[0].Pos()).Name())
+		var found bool
+		path, found = config.Find(dir)
+		if !found {
+			return &config.Config{}, nil
+		}
+	}
+
+	v, _ := configs.LoadOrStore(path, &configEntry{})
+	entry := v.(*configEntry)
+	entry.once.Do(func() {
+		entry.cfg, entry.err = config.Load(path)
+	})
+	if err := entry.err; err != nil {
+		return nil, fmt.Errorf("load configuration: %w", err)
+	}
+
+	return entry.cfg, nil
+}
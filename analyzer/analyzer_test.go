@@ -0,0 +1,59 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a", "loggers", "summaries/...", "layers/...", "unique/...", "messages", "ctors")
+}
+
+func TestAnalyzerModuleUniqueness(t *testing.T) {
+	analysistest.Run(t, filepath.Join(analysistest.TestData(), "module"), Analyzer, "./...")
+}
+
+func TestAnalyzerDebugCIR(t *testing.T) {
+	if err := Analyzer.Flags.Set("debug-cir", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer Analyzer.Flags.Set("debug-cir", "false")
+
+	// Invariant violations would be unexpected diagnostics.
+	analysistest.Run(t, analysistest.TestData(), Analyzer, "a", "loggers")
+}
+
+func TestAnalyzerSuggestedFixes(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), Analyzer, "fixes", "terms/...")
+}
+
+func TestAnalyzerRelated(t *testing.T) {
+	results := analysistest.Run(t, analysistest.TestData(), Analyzer, "a")
+
+	var found bool
+	for _, res := range results {
+		for _, d := range res.Diagnostics {
+			if !strings.HasPrefix(d.Message, "CER150: NoLogAndReturn — error err is logged and then returned") {
+				continue
+			}
+
+			found = true
+			if len(d.Related) != 2 {
+				t.Errorf("expected log and return sites related to %q, got %d", d.Message, len(d.Related))
+				continue
+			}
+			if d.Related[0].Message != "error err is logged here" || d.Related[1].Message != "and returned here" {
+				t.Errorf("unexpected related information %q, %q", d.Related[0].Message, d.Related[1].Message)
+			}
+			if d.Related[0].Pos >= d.Related[1].Pos || d.Related[1].Pos != d.Pos {
+				t.Errorf("unexpected related positions")
+			}
+		}
+	}
+	if !found {
+		t.Error("no log and return diagnostics found")
+	}
+}
@@ -0,0 +1,23 @@
+// Package analyzer exposes cerrful as a [golang.org/x/tools/go/analysis] analyzer.
+//
+// The analyzer glues the internal machinery together:
+//
+//   - AST scrapping collects CIR nodes for every error-related construct
+//     of a package into a tracing context.
+//   - The SSA interpreter walks each function of the package over that
+//     context and tracks the state of its errors. Functions are summarized
+//     first, so calls of local helpers are seen through. Summaries of
+//     exported functions are exported as facts for importing packages.
+//   - Annotation messages are checked to be unique within the configured
+//     scope, annotation sites are exported as package facts for the
+//     module scope.
+//   - Everything reported along the way is turned into analysis diagnostics.
+//
+// It can be used directly with any analysis driver:
+//
+//	singlechecker.Main(analyzer.Analyzer)
+//
+// or via the cerrful command, which also works as a vet tool:
+//
+//	go vet -vettool=$(which cerrful) ./...
+package analyzer
@@ -0,0 +1,19 @@
+package app // want package:"annotations\\(2\\)"
+
+import (
+	"fmt"
+
+	"example.com/module/lib"
+)
+
+func start(name string) error {
+	if err := lib.Load(name); err != nil {
+		return fmt.Errorf("load the config: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "load the config" collides with "load config" at example.com/module/lib/lib.go:18:10 after normalization`
+	}
+
+	if err := lib.Load(name + ".local"); err != nil {
+		return fmt.Errorf("load local config: %w", err)
+	}
+
+	return nil
+}
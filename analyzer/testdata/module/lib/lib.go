@@ -0,0 +1,22 @@
+package lib // want package:"annotations\\(1\\)"
+
+import (
+	"errors"
+	"fmt"
+)
+
+func read(name string) error {
+	if name == "" {
+		return errors.New("empty name")
+	}
+
+	return nil
+}
+
+func Load(name string) error { // want Load:"errors: wrapped"
+	if err := read(name); err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,602 @@
+package a
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+)
+
+func do() error {
+	return errors.New("do")
+}
+
+func swapped() (error, int) {
+	return nil, 0
+}
+
+func wrapped() error {
+	if err := do(); err != nil {
+		return fmt.Errorf("do: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
+
+func wrappedBadFormat() error {
+	if err := do(); err != nil {
+		return fmt.Errorf("%w: do", err) // want `CER102: AnnotationFormatMustEndWithW`
+	}
+
+	return nil
+}
+
+func wrappedNonLiteral(format string) error {
+	if err := do(); err != nil {
+		return fmt.Errorf(format, err) // want `CER0101: AnnotationFormatMustBeLiteral`
+	}
+
+	return nil
+}
+
+func errorNotLast() {
+	err, _ := swapped() // want `CER090: ErrorMustBeLastReturnValue`
+	if err != nil {
+		panic(err)
+	}
+}
+
+func panicked() error {
+	err := do()
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
+
+func redundantCheck() error {
+	err := errors.New("redundant")
+	if err != nil { // want `CER075: NoRedundantErrorCheck — error err is already known to be not nil here`
+		return err
+	}
+
+	return nil
+}
+
+func nestedCheck() error {
+	if err := do(); err != nil {
+		if err == nil { // want `CER075: NoRedundantErrorCheck — error err is known to be not nil here`
+			return nil
+		}
+		return fmt.Errorf("do: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
+
+func twoPaths(flag bool) error {
+	var err error
+	if flag {
+		err = errors.New("flag")
+	} else {
+		err = do()
+	}
+	if err != nil {
+		return fmt.Errorf("two paths: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "two paths" is not unique, it is also used at a.go:105:10`
+	}
+
+	return nil
+}
+
+func twoPathsSwapped(flag bool) error {
+	var err error
+	if flag {
+		err = do()
+	} else {
+		err = errors.New("flag")
+	}
+	if err != nil {
+		return fmt.Errorf("two paths: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "two paths" is not unique, it is also used at a.go:91:10`
+	}
+
+	return nil
+}
+
+func redundantOnAllPaths(flag bool) error {
+	var err error
+	if flag {
+		err = errors.New("flag")
+	} else {
+		err = errors.New("no flag")
+	}
+	if err != nil { // want `CER075: NoRedundantErrorCheck — error err is already known to be not nil here`
+		return err
+	}
+
+	return nil
+}
+
+func loop(n int) error {
+	for i := 0; i < n; i++ {
+		if err := do(); err != nil {
+			return fmt.Errorf("do %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func shadowed() error {
+	err := errors.New("outer") // want `CER000: NoSilentDrop — error err is never checked`
+	if err := do(); err != nil {
+		return fmt.Errorf("do: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+	if err != nil { // want `CER075: NoRedundantErrorCheck — error err is already known to be not nil here`
+		return err
+	}
+
+	return nil
+}
+
+func reassigned() error {
+	err := do()
+	if err != nil {
+		err = errors.New("fallback")
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func reassignedInLoop(n int) error {
+	var err error
+	for i := 0; i < n; i++ {
+		err = do()
+		if err != nil {
+			continue
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("do: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
+
+func singleBare() error {
+	if err := do(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Errors of local helpers creating or annotating them, like do and wrapped, are not
+// propagated as is.
+func multiBare() error {
+	if err := do(); err != nil {
+		return err
+	}
+	if err := wrapped(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func sameOriginTwice(flag bool) error {
+	err := do()
+	if err != nil {
+		if flag {
+			return err
+		}
+		return err
+	}
+
+	return nil
+}
+
+func missing(name string) error {
+	return fmt.Errorf("missing %s", name)
+}
+
+func created(key, value string) error {
+	if key == "" {
+		return missing("key")
+	}
+	if value == "" {
+		return missing("value")
+	}
+
+	return nil
+}
+
+// call passes errors of the function through as is.
+func call(f func() error) error {
+	return f()
+}
+
+func multiPassthrough(open, read func() error) error {
+	if err := call(open); err != nil {
+		return err // want `CER030: MultiReturnMustAnnotate — error of a.call is returned as is along with 1 more errors`
+	}
+	if err := call(read); err != nil {
+		return err // want `CER030: MultiReturnMustAnnotate — error of a.call is returned as is along with 1 more errors`
+	}
+
+	return nil
+}
+
+func samePassthroughTwice(read func() error, flag bool) error {
+	err := call(read)
+	if err != nil {
+		if flag {
+			return err // want `CER020: SingleLocalPassthrough — error of a.call is returned as is from 2 places`
+		}
+		return err // want `CER020: SingleLocalPassthrough — error of a.call is returned as is from 2 places`
+	}
+
+	return nil
+}
+
+// retry passes errors of the function through as is, like call, recursive calls do not hide it.
+func retry(n int, f func() error) error {
+	if n <= 1 {
+		return f() // want `CER030: MultiReturnMustAnnotate — error of parameter f : func\(\) error is returned as is along with 1 more errors`
+	}
+	if err := f(); err == nil {
+		return nil
+	}
+
+	return retry(n-1, f) // want `CER030: MultiReturnMustAnnotate — error of a.retry is returned as is along with 1 more errors`
+}
+
+func multiRetry(open, read func() error) error {
+	if err := retry(3, open); err != nil {
+		return err // want `CER030: MultiReturnMustAnnotate — error of a.retry is returned as is along with 1 more errors`
+	}
+	if err := retry(3, read); err != nil {
+		return err // want `CER030: MultiReturnMustAnnotate — error of a.retry is returned as is along with 1 more errors`
+	}
+
+	return nil
+}
+
+func external() error {
+	if _, err := os.Open("file"); err != nil {
+		return err // want `CER010: AnnotateExternal — error of os.Open is returned as is across the boundary between os and a, annotate it`
+	}
+
+	return nil
+}
+
+func parameter(err error) error {
+	return err
+}
+
+func discarded() {
+	_ = do()                    // want `CER000: NoSilentDrop — error of do is discarded`
+	_, _ = os.ReadFile("a.txt") // want `CER000: NoSilentDrop — error of os.ReadFile is discarded`
+}
+
+func dropped() {
+	do()               // want `CER000: NoSilentDrop — error of do is not used`
+	os.Remove("a.txt") // want `CER000: NoSilentDrop — error of os.Remove is not used`
+}
+
+func unusedOnSomePath(flag bool) error {
+	err := do() // want `CER000: NoSilentDrop — error err is never checked, logged, returned or passed on along some path`
+	if flag {
+		return fmt.Errorf("do: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
+
+// Paths are too many to explore one by one, dropped errors are found nonetheless.
+func unusedOnManyPaths(flags []bool) {
+	if flags[0] {
+		err := do() // want `CER000: NoSilentDrop — error err is never checked, logged, returned or passed on along some path`
+		if flags[1] {
+			log.Println(err)
+		}
+	}
+	if flags[2] {
+		log.Println(do())
+	}
+	if flags[3] {
+		log.Println(do())
+	}
+	if flags[4] {
+		log.Println(do())
+	}
+	if flags[5] {
+		log.Println(do())
+	}
+	if flags[6] {
+		log.Println(do())
+	}
+	if flags[7] {
+		log.Println(do())
+	}
+	if flags[8] {
+		log.Println(do())
+	}
+	if flags[9] {
+		log.Println(do())
+	}
+	if flags[10] {
+		log.Println(do())
+	}
+	if flags[11] {
+		log.Println(do())
+	}
+	if flags[12] {
+		log.Println(do())
+	}
+	if flags[13] {
+		log.Println(do())
+	}
+	if flags[14] {
+		log.Println(do())
+	}
+	if flags[15] {
+		log.Println(do())
+	}
+}
+
+func overwritten() error {
+	err := do() // want `CER000: NoSilentDrop — error err is never checked, logged, returned or passed on along some path`
+	err = wrapped()
+	if err != nil {
+		return fmt.Errorf("wrapped: %w", err)
+	}
+
+	return nil
+}
+
+func respond(w http.ResponseWriter, data []byte) {
+	w.Write(data)
+	fmt.Fprintln(w, "done")
+}
+
+func allowed(data []byte) []byte {
+	var buf bytes.Buffer
+	buf.Write(data)
+	_, _ = buf.WriteString("data")
+	fmt.Println("written")
+
+	fmt.Fprintf(&buf, "%d bytes", len(data))
+
+	h := sha256.New()
+	h.Write(buf.Bytes())
+	_, _ = h.Write(data)
+	return h.Sum(nil)
+}
+
+func logAndReturn() error {
+	if err := do(); err != nil {
+		log.Println("do:", err)
+		return err // want `CER150: NoLogAndReturn — error err is logged and then returned`
+	}
+
+	return nil
+}
+
+func logOnBranch(verbose bool) error {
+	if err := do(); err != nil {
+		if verbose {
+			log.Println(err)
+		}
+		return fmt.Errorf("do: %w", err) // want `CER150: NoLogAndReturn — error err is logged and then returned` `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
+
+func logTwice() {
+	if err := do(); err != nil {
+		log.Println(err)
+		log.Printf("do: %v", err) // want `CER150: NoLogAndReturn — error err is logged more than once`
+	}
+}
+
+func logOrReturn(verbose bool) error {
+	if err := do(); err != nil {
+		if verbose {
+			log.Println(err)
+			return nil
+		}
+		return fmt.Errorf("do: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
+
+func isTwice() error {
+	err := do()
+	if errors.Is(err, io.EOF) {
+		if errors.Is(err, io.EOF) { // want `CER075: NoRedundantErrorCheck — error err is already known to be of "io".EOF`
+			return nil
+		}
+	}
+
+	return fmt.Errorf("do: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+}
+
+func asThenAssert() error {
+	err := do()
+	var pe *fs.PathError
+	if errors.As(err, &pe) {
+		if _, ok := err.(*fs.PathError); ok { // want `CER075: NoRedundantErrorCheck — error err was checked to be of "io/fs".PathError already`
+			return nil
+		}
+	}
+
+	return fmt.Errorf("do: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+}
+
+func switchThenCheck() error {
+	err := do()
+	switch err {
+	case io.EOF:
+		if err != nil { // want `CER075: NoRedundantErrorCheck — error err is already known to be not nil here`
+			return nil
+		}
+	case nil:
+		return nil
+	}
+
+	return fmt.Errorf("do: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+}
+
+func typeSwitch() error {
+	err := do()
+	switch e := err.(type) {
+	case nil:
+		return nil
+	case *fs.PathError:
+		if e.Err == nil || err == nil { // want `CER075: NoRedundantErrorCheck — error err is known to be not nil here`
+			return nil
+		}
+	}
+
+	return fmt.Errorf("do: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+}
+
+func multiWrapped() error {
+	err1 := do()
+	err2 := do()
+	if err1 != nil || err2 != nil {
+		return fmt.Errorf("first: %w, second: %w", err1, err2)
+	}
+
+	return nil
+}
+
+func multiWrappedBadFormat() error {
+	err1 := do()
+	err2 := do()
+	if err1 != nil || err2 != nil {
+		return fmt.Errorf("do: %w, %w", err1, err2) // want `CER102: AnnotationFormatMustEndWithW`
+	}
+
+	return nil
+}
+
+func eitherFailed() error {
+	err1 := do()
+	err2 := do()
+	if err1 != nil || err2 != nil {
+		return errors.New("do failed")
+	}
+
+	return nil
+}
+
+func bothFailed() bool {
+	err1 := do()
+	err2 := do()
+	return err1 != nil && (err2 != nil)
+}
+
+func joined() error {
+	err1 := do()
+	err2 := do()
+	return errors.Join(err1, err2)
+}
+
+func logAndJoin() error {
+	err1 := do()
+	if err1 != nil {
+		log.Println(err1)
+	}
+	err2 := do()
+	return errors.Join(err1, err2) // want `CER150: NoLogAndReturn — error err1 is logged and then returned`
+}
+
+type codeError int
+
+func (e codeError) Error() string {
+	return fmt.Sprintf("code %d", int(e))
+}
+
+func typedCreated(path string) error {
+	var err error = &fs.PathError{Op: "open", Path: path, Err: fs.ErrNotExist}
+	var pe *fs.PathError
+	if errors.As(err, &pe) { // want `CER075: NoRedundantErrorCheck — error err is already known to be exactly "io/fs".PathError`
+		return err
+	}
+
+	return nil
+}
+
+func typedConverted(code int) error {
+	err := error(codeError(code))
+	if _, ok := err.(*fs.PathError); ok { // want `CER075: NoRedundantErrorCheck — error err is already known to be exactly of another value, it cannot be "io/fs".PathError`
+		return nil
+	}
+
+	return err
+}
+
+// ErrClosed is a sentinel, unlike lastErr and ErrCurrent that change.
+var (
+	ErrClosed  = errors.New("closed")
+	ErrCurrent = errors.New("initial")
+	lastErr    error
+)
+
+func remember(err error) {
+	lastErr = err
+	ErrCurrent = err
+}
+
+func last() error {
+	err := lastErr
+	if err != nil {
+		return fmt.Errorf("last: %w", err)
+	}
+
+	return nil
+}
+
+// previous returns whatever was remembered, its errors are not created here.
+func previous() error {
+	return lastErr
+}
+
+func previousTwice(flag bool) error {
+	err := previous()
+	if flag {
+		return err // want `CER020: SingleLocalPassthrough — error of a.previous is returned as is from 2 places`
+	}
+
+	return err // want `CER020: SingleLocalPassthrough — error of a.previous is returned as is from 2 places`
+}
+
+func current() error {
+	err := ErrCurrent
+	if err == nil {
+		return nil
+	}
+
+	return err
+}
+
+func closed() error {
+	err := ErrClosed
+	if err != nil { // want `CER075: NoRedundantErrorCheck — error ErrClosed is already known to be not nil here`
+		return err
+	}
+
+	return nil
+}
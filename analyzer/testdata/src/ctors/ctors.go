@@ -0,0 +1,29 @@
+package ctors
+
+import "fmt"
+
+// NotFound is a constructor taking no arguments.
+func NotFound() error { // want NotFound:"errors: created"
+	return fmt.Errorf("not found")
+}
+
+// Annotate is a fmt-style wrapper which can be called without arguments.
+func Annotate(args ...any) error { // want Annotate:"errors: created"
+	if len(args) == 0 {
+		return fmt.Errorf("nothing to annotate")
+	}
+
+	return fmt.Errorf(args[0].(string), args[1:]...)
+}
+
+func find(name string) error {
+	if name == "" {
+		return NotFound()
+	}
+
+	return nil
+}
+
+func annotate() error {
+	return Annotate()
+}
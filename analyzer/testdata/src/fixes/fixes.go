@@ -0,0 +1,42 @@
+package fixes
+
+import (
+	"errors"
+	"fmt"
+)
+
+func do() error {
+	return errors.New("do")
+}
+
+func formattedV() error {
+	if err := do(); err != nil {
+		return fmt.Errorf("do: %v", err) // want `CER103: NoOpaqueErrorFormat — error err is formatted with %v, errors.Is and errors.As cannot see through the annotation, use %w instead` `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at fixes.go:22:10`
+	}
+
+	return nil
+}
+
+func formattedErrorMethod() error {
+	if err := do(); err != nil {
+		return fmt.Errorf("do: %s", err.Error()) // want `CER103: NoOpaqueErrorFormat — error err is formatted with err.Error\(\), errors.Is` `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at fixes.go:14:10`
+	}
+
+	return nil
+}
+
+func formattedRaw(name string) error {
+	if err := do(); err != nil {
+		return fmt.Errorf(`do %q: %+v`, name, err) // want `CER103: NoOpaqueErrorFormat — error err is formatted with %\+v`
+	}
+
+	return nil
+}
+
+func formattedNoAnnotation() error {
+	if err := do(); err != nil {
+		return fmt.Errorf("%v", err) // want `CER103: NoOpaqueErrorFormat` `CER102: AnnotationFormatMustEndWithW`
+	}
+
+	return nil
+}
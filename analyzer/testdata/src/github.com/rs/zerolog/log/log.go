@@ -0,0 +1,8 @@
+// Package log is a stub of github.com/rs/zerolog/log for tests.
+package log
+
+import "github.com/rs/zerolog"
+
+func Info() *zerolog.Event         { return &zerolog.Event{} }
+func Error() *zerolog.Event        { return &zerolog.Event{} }
+func Err(err error) *zerolog.Event { return &zerolog.Event{} }
@@ -0,0 +1,17 @@
+// Package zerolog is a stub of github.com/rs/zerolog for tests.
+package zerolog
+
+type Event struct{}
+
+func (e *Event) Str(key, value string) *Event       { return e }
+func (e *Event) Err(err error) *Event               { return e }
+func (e *Event) AnErr(key string, err error) *Event { return e }
+func (e *Event) Msg(msg string)                     {}
+func (e *Event) Msgf(format string, v ...any)       {}
+func (e *Event) Send()                              {}
+
+type Logger struct{}
+
+func (l *Logger) Info() *Event         { return &Event{} }
+func (l *Logger) Error() *Event        { return &Event{} }
+func (l *Logger) Err(err error) *Event { return &Event{} }
@@ -0,0 +1,47 @@
+package layers
+
+import (
+	"layers/service"
+	"layers/storage/pg"
+)
+
+func loadWithinLayer(key string) ([]byte, error) {
+	data, err := service.Load(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func loadAcrossLayers(key string) ([]byte, error) {
+	data, err := pg.Get(key)
+	if err != nil {
+		return nil, err // want `CER010: AnnotateExternal — error of layers/storage/pg.Get is returned as is across the boundary between storage and app, annotate it`
+	}
+
+	return data, nil
+}
+
+func check(key string) error {
+	_, err := service.Load(key)
+	return err
+}
+
+func loadChecked(key string) ([]byte, error) {
+	if err := check(key); err != nil {
+		return nil, err // want `CER040: AnnotationRequiredForExternalAndMultiLocal — error of layers.check is returned as is from one of 2 places along with the error of layers/storage/pg.Get, annotate them`
+	}
+
+	data, err := service.Load(key)
+	if err != nil {
+		return nil, err // want `CER040: AnnotationRequiredForExternalAndMultiLocal — error of layers/service.Load is returned as is from one of 2 places`
+	}
+
+	raw, err := pg.Get(key)
+	if err != nil {
+		return nil, err // want `CER010: AnnotateExternal — error of layers/storage/pg.Get is returned as is across the boundary between storage and app, annotate it`
+	}
+
+	return append(data, raw...), nil
+}
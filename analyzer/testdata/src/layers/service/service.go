@@ -0,0 +1,16 @@
+package service
+
+import (
+	"fmt"
+
+	"layers/storage/pg"
+)
+
+func Load(key string) ([]byte, error) { // want Load:"errors: wrapped"
+	data, err := pg.Get(key)
+	if err != nil {
+		return nil, fmt.Errorf("get %s: %w", key, err)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,11 @@
+package pg
+
+import "errors"
+
+func Get(key string) ([]byte, error) { // want Get:"errors: created"
+	if key == "" {
+		return nil, errors.New("empty key")
+	}
+
+	return []byte(key), nil
+}
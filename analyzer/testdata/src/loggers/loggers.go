@@ -0,0 +1,87 @@
+package loggers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/rs/zerolog"
+	zlog "github.com/rs/zerolog/log"
+	"go.uber.org/zap"
+)
+
+func do() error {
+	return errors.New("do")
+}
+
+func zapLogged(logger *zap.Logger) error {
+	if err := do(); err != nil {
+		logger.Error("do", zap.String("op", "do"), zap.Error(err))
+		return fmt.Errorf("do: %w", err) // want `CER150: NoLogAndReturn — error err is logged and then returned` `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
+
+func zapNamed(logger *zap.Logger) error {
+	if err := do(); err != nil {
+		logger.Warn("do", zap.NamedError("cause", err))
+		return fmt.Errorf("do: %w", err) // want `CER150: NoLogAndReturn — error err is logged and then returned` `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
+
+func zapOther(logger *zap.Logger) error {
+	if err := do(); err != nil {
+		logger.Info("do", zap.String("op", "do"))
+		return fmt.Errorf("do: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
+
+func zerologChain(logger *zerolog.Logger) error {
+	if err := do(); err != nil {
+		logger.Error().Str("op", "do").Err(err).Msg("do")
+		return fmt.Errorf("do: %w", err) // want `CER150: NoLogAndReturn — error err is logged and then returned` `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
+
+func zerologGlobal() error {
+	if err := do(); err != nil {
+		zlog.Err(err).Send()
+		return fmt.Errorf("do: %w", err) // want `CER150: NoLogAndReturn — error err is logged and then returned` `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
+
+func zerologNoError() error {
+	if err := do(); err != nil {
+		zlog.Info().Str("op", "do").Msg("do")
+		return fmt.Errorf("do: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
+
+func slogAny() error {
+	if err := do(); err != nil {
+		slog.Error("do", slog.Any("err", err))
+		return fmt.Errorf("do: %w", err) // want `CER150: NoLogAndReturn — error err is logged and then returned` `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
+
+func slogPairs(logger *slog.Logger) error {
+	if err := do(); err != nil {
+		logger.Warn("do", "err", err)
+		return fmt.Errorf("do: %w", err) // want `CER150: NoLogAndReturn — error err is logged and then returned` `CER104: AnnotationMessageMustBeUnique — annotation message "do" is not unique, it is also used at`
+	}
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package messages
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	errHTTP   = errors.New("HTTP request")
+	errCase   = errors.New("Bad request")                                                               // want `CER110: MessageMustStartLowercase — message "Bad request" must start with a lowercase letter`
+	errDot    = errors.New("bad request.")                                                              // want `CER111: NoTrailingPunctuation — message "bad request." must not end with punctuation`
+	errLine   = errors.New("bad request\n")                                                             // want `CER111: NoTrailingPunctuation — message "bad request\\n" must not end with a newline`
+	errNoise  = errors.New("could not parse")                                                           // want `CER112: NoNoiseWords — message "could not parse" must not say "could not", an error is a failure already`
+	errVerbs  = errors.New("bad value %d")                                                              // want `CER113: NoFormatVerbsWithoutArgs — message "bad value %d" has format verbs, but there are no arguments to format`
+	errSpace  = errors.New(" bad request")                                                              // want `CER114: NoSurroundingWhitespace — message " bad request" must not have leading or trailing whitespace`
+	errLength = errors.New("request has been rejected since it does not match any of the routes known") // want `CER115: MessageMaxLength — message ".*" is 73 characters long, 64 at most are allowed`
+)
+
+func value(v int) error {
+	if v < 0 {
+		return fmt.Errorf("negative value %d", v)
+	}
+	if v == 0 {
+		return fmt.Errorf("zero value %d") // want `CER113: NoFormatVerbsWithoutArgs`
+	}
+
+	return nil
+}
+
+func parse(v int) error {
+	if err := value(v); err != nil {
+		return fmt.Errorf("Failed to parse value: %w", err) // want `CER110: MessageMustStartLowercase — message "Failed to parse value"` `CER112: NoNoiseWords — message "Failed to parse value" must not say "failed to"`
+	}
+
+	return nil
+}
+
+func read(v int) error {
+	if err := parse(v); err != nil {
+		return fmt.Errorf("read error : %w", err) // want `CER112: NoNoiseWords — message "read error " must not say "error"` `CER114: NoSurroundingWhitespace`
+	}
+
+	return nil
+}
+
+func joined(name string) error {
+	if name == "" {
+		return fmt.Errorf("unexpected joined error %q", name)
+	}
+
+	return errors.New("unexpected error value")
+}
+
+func reread(v int) error {
+	if err := read(v); err != nil {
+		return fmt.Errorf("error reading value: %w", err) // want `CER112: NoNoiseWords — message "error reading value" must not say "error"`
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package lib
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+)
+
+var ErrNotFound = errors.New("not found")
+
+func Check(err error) error { // want Check:"errors: passed\\(0\\)"
+	return err
+}
+
+func Find(name string) error { // want Find:"errors: created sentinel\\(\"summaries/lib\".ErrNotFound\\)"
+	if name == "" {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func Open(path string) error { // want Open:"errors: created wrapped type\\(\"io/fs\".PathError\\)"
+	if path == "" {
+		return &fs.PathError{Op: "open", Path: path, Err: fs.ErrInvalid}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close: %w", err)
+	}
+
+	return nil
+}
+
+func Read(r io.Reader) error { // want Read:"errors: wrapped logged"
+	if _, err := r.Read(nil); err != nil {
+		log.Println(err)
+		return fmt.Errorf("read: %w", err) // want `CER150: NoLogAndReturn — error err is logged and then returned`
+	}
+
+	return nil
+}
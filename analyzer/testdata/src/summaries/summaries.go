@@ -0,0 +1,37 @@
+package summaries
+
+import (
+	"fmt"
+	"io"
+	"log"
+
+	"summaries/lib"
+)
+
+func checked() error {
+	err := lib.Find("name")
+	if err != nil {
+		return lib.Check(fmt.Errorf("find: %w", err))
+	}
+
+	return nil
+}
+
+func checkedBare() error {
+	err := lib.Find("name")
+	return lib.Check(err) // want `CER010: AnnotateExternal — error of summaries/lib.Find is returned as is across the boundary between summaries/lib and summaries, annotate it`
+}
+
+func readAndLog(r io.Reader) {
+	if err := lib.Read(r); err != nil {
+		log.Println(err) // want `CER150: NoLogAndReturn — error err is logged by summaries/lib.Read already`
+	}
+}
+
+func readAndReturn(r io.Reader) error {
+	if err := lib.Read(r); err != nil {
+		return fmt.Errorf("read: %w", err)
+	}
+
+	return nil
+}
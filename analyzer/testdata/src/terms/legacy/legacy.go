@@ -0,0 +1,20 @@
+package legacy
+
+import "errors"
+
+var (
+	errConnect = errors.New("couldn't connect")
+	errDial    = errors.New("could not dial") // want `CER112: NoNoiseWords — message "could not dial" must not say "could not", an error is a failure already`
+	errLegacy  = errors.New("legacy connect") // want `CER120: NoForbiddenTerms — message "legacy connect" contains forbidden term "legacy": everything is legacy here`
+)
+
+func connect(legacy, dial bool) error {
+	if legacy {
+		return errLegacy
+	}
+	if dial {
+		return errDial
+	}
+
+	return errConnect
+}
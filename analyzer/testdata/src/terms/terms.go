@@ -0,0 +1,36 @@
+package terms
+
+import (
+	"errors"
+	"fmt"
+)
+
+var errConnect = errors.New("couldn't connect") // want `CER120: NoForbiddenTerms — message "couldn't connect" contains forbidden term "couldn't": an error is a failure already`
+
+func connect() error {
+	return errConnect
+}
+
+func dial() error {
+	if err := connect(); err != nil {
+		return fmt.Errorf("failed to dial db: %w", err) // want `CER112: NoNoiseWords` `CER120: NoForbiddenTerms — message "failed to dial db" contains forbidden term "failed to"` `CER120: NoForbiddenTerms — message "failed to dial db" contains forbidden term "db": name the database`
+	}
+
+	return nil
+}
+
+func ping() error {
+	if err := dial(); err != nil {
+		return fmt.Errorf("ping err: %w", err) // want `CER120: NoForbiddenTerms — message "ping err" contains forbidden term "err"`
+	}
+
+	return nil
+}
+
+func check() error {
+	if err := ping(); err != nil {
+		return fmt.Errorf("oops, check: %w", err) // want `CER120: NoForbiddenTerms — message "oops, check" contains forbidden term "oops": messages are read by people on call`
+	}
+
+	return nil
+}
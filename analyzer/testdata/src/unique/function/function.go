@@ -0,0 +1,42 @@
+package function
+
+import (
+	"errors"
+	"fmt"
+)
+
+func get(id int) error {
+	if id < 0 {
+		return errors.New("negative id")
+	}
+
+	return nil
+}
+
+func getUser(id int) error {
+	if err := get(id); err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	return nil
+}
+
+// Messages of other functions do not collide with ones of getUser.
+func getUserAgain(id int) error {
+	if err := get(id); err != nil {
+		return fmt.Errorf("get user: %w", err)
+	}
+
+	return nil
+}
+
+func getUsers(first, second int) error {
+	if err := get(first); err != nil {
+		return fmt.Errorf("get the user: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "get the user" collides with "get a user" at function.go:38:10 after normalization`
+	}
+	if err := get(second); err != nil {
+		return fmt.Errorf("get a user: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "get a user" collides with "get the user" at function.go:35:10 after normalization`
+	}
+
+	return nil
+}
@@ -0,0 +1,38 @@
+package unique
+
+import (
+	"errors"
+	"fmt"
+)
+
+func get(id int) error {
+	if id < 0 {
+		return errors.New("negative id")
+	}
+
+	return nil
+}
+
+func getUser(id int) error {
+	if err := get(id); err != nil {
+		return fmt.Errorf("get user: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "get user" collides with "Get  the User" at unique.go:26:10 after normalization`
+	}
+
+	return nil
+}
+
+func getUserAgain(id int) error {
+	if err := get(id); err != nil {
+		return fmt.Errorf("Get  the User: %w", err) // want `CER104: AnnotationMessageMustBeUnique — annotation message "Get  the User" collides with "get user" at unique.go:18:10 after normalization`
+	}
+
+	return nil
+}
+
+func getGroup(id int) error {
+	if err := get(id); err != nil {
+		return fmt.Errorf("get group: %w", err)
+	}
+
+	return nil
+}
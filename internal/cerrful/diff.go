@@ -0,0 +1,176 @@
+package cerrful
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/sirkon/cerrful/internal/cir"
+)
+
+// Diff compares programs node by node and describes their differences. Positions
+// are not compared. Returns nil if programs are the same.
+//
+//	aliasError/1/body/0: want Assign [newErr] <- oldErr, got Assign [newErr] <- err
+func Diff(want, got *CIRProgram) []string {
+	d := &differ{
+		want: &printer{pkg: want.Package, indented: true},
+		got:  &printer{pkg: got.Package, indented: true},
+	}
+
+	for i := range max(len(want.Functions), len(got.Functions)) {
+		switch {
+		case i >= len(got.Functions):
+			d.add("", "missing function %s", want.Functions[i].Name)
+		case i >= len(want.Functions):
+			d.add("", "unexpected function %s", got.Functions[i].Name)
+		case want.Functions[i].Name != got.Functions[i].Name:
+			d.add("", "function #%d: want %s, got %s", i, want.Functions[i].Name, got.Functions[i].Name)
+		default:
+			d.nodes(want.Functions[i].Name, want.Functions[i].Nodes, got.Functions[i].Nodes)
+		}
+	}
+
+	return d.res
+}
+
+type differ struct {
+	want *printer
+	got  *printer
+	res  []string
+}
+
+func (d *differ) nodes(path string, want, got []Node) {
+	for i := range max(len(want), len(got)) {
+		p := fmt.Sprintf("%s/%d", path, i)
+		switch {
+		case i >= len(got):
+			d.add(p, "missing %s", summary(d.want, want[i]))
+		case i >= len(want):
+			d.add(p, "unexpected %s", summary(d.got, got[i]))
+		default:
+			d.node(p, want[i], got[i])
+		}
+	}
+}
+
+func (d *differ) node(path string, want, got Node) {
+	switch w := want.(type) {
+	case *cir.If:
+		g, ok := got.(*cir.If)
+		if !ok || w.Cond != g.Cond {
+			break
+		}
+
+		d.nodes(path+"/body", w.Body, g.Body)
+		d.nodes(path+"/else", w.Else, g.Else)
+		return
+
+	case *cir.Switch:
+		g, ok := got.(*cir.Switch)
+		if !ok || w.Tag != g.Tag {
+			break
+		}
+
+		for i := range max(len(w.Cases), len(g.Cases)) {
+			p := fmt.Sprintf("%s/case %d", path, i)
+			switch {
+			case i >= len(g.Cases):
+				d.add(p, "missing clause %s", caseSummary(w.Cases[i]))
+			case i >= len(w.Cases):
+				d.add(p, "unexpected clause %s", caseSummary(g.Cases[i]))
+			case !equalValues(reflect.ValueOf(w.Cases[i].List), reflect.ValueOf(g.Cases[i].List)):
+				d.add(p, "want clause %s, got %s", caseSummary(w.Cases[i]), caseSummary(g.Cases[i]))
+			default:
+				d.nodes(p, w.Cases[i].Body, g.Cases[i].Body)
+			}
+		}
+		return
+
+	default:
+		if equalValues(reflect.ValueOf(want), reflect.ValueOf(got)) {
+			return
+		}
+	}
+
+	d.add(path, "want %s, got %s", summary(d.want, want), summary(d.got, got))
+}
+
+func (d *differ) add(path, format string, a ...any) {
+	msg := fmt.Sprintf(format, a...)
+	if path != "" {
+		msg = path + ": " + msg
+	}
+	d.res = append(d.res, msg)
+}
+
+// summary renders the node in a single line. Nested nodes are not shown.
+func summary(p *printer, node Node) string {
+	p.buf.Reset()
+	p.node(node)
+
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSpace(p.buf.String()), "\n") {
+		if strings.HasPrefix(line, " ") {
+			continue
+		}
+		lines = append(lines, strings.TrimSuffix(line, ":"))
+	}
+
+	return strings.Join(lines, "; ")
+}
+
+func caseSummary(c cir.SwitchCase) string {
+	if len(c.List) == 0 {
+		return "Default"
+	}
+
+	return fmt.Sprintf("Case %q", c.List)
+}
+
+// equalValues compares values deeply like [reflect.DeepEqual] does, except spans
+// are ignored and nil slices are equal to empty ones.
+func equalValues(a, b reflect.Value) bool {
+	if a.IsValid() != b.IsValid() {
+		return false
+	}
+	if !a.IsValid() {
+		return true
+	}
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	switch a.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equalValues(a.Elem(), b.Elem())
+
+	case reflect.Struct:
+		for i := range a.NumField() {
+			if a.Type().Field(i).Type == spanType {
+				continue
+			}
+			if !equalValues(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := range a.Len() {
+			if !equalValues(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+
+	default:
+		return a.Interface() == b.Interface()
+	}
+}
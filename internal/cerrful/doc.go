@@ -0,0 +1,17 @@
+// Package cerrful translates Go functions into CIR — the cerrful intermediate
+// representation of error handling described in the brief — and renders it in
+// a human-readable form.
+//
+// The CIR of a file is a [CIRProgram] made of [CIRFunction] entries, each one
+// holding the error handling skeleton of the function:
+//
+//	Function foo:
+//	  Assign [err] <- os.Open(…) (foreign call)
+//	  If "err != nil":
+//	    Log [err] level=warn msg="open file" (via fmt.Println)
+//	    Return [err]
+//
+// It is mostly meant for debugging: [DemoTranslate] shows what the analyzer sees
+// in the given source code. The text form can be parsed back with [ParsePretty],
+// so expected CIR can be written as text and compared using [Diff].
+package cerrful
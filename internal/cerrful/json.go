@@ -0,0 +1,545 @@
+package cerrful
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/token"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/sirkon/cerrful/internal/cir"
+	"github.com/sirkon/cerrful/internal/tracing"
+)
+
+// JSONVersion is the version of the JSON encoding of CIR. It follows the version
+// of the CIR brief the encoding represents. Names of node fields are made of names of
+// fields of CIR types, testdata/schema.golden keeps them from changing unnoticed.
+const JSONVersion = 21
+
+// nodeTypes lists all node types of the CIR. Their kinds in the JSON encoding are
+// made of type names: "ExprWrap" is "expr_wrap".
+var nodeTypes = []cir.Node{
+	&cir.Assign{},
+	&cir.AssignCheckFlag{},
+	&cir.AssignAssert{},
+	&cir.Log{},
+	&cir.Return{},
+	&cir.If{},
+	&cir.Switch{},
+	&cir.ExprVar{},
+	&cir.ExprVarHidden{},
+	&cir.ExprNil{},
+	&cir.ExprAlias{},
+	&cir.ExprSentinel{},
+	&cir.ExprType{},
+	&cir.ExprCall{},
+	&cir.ExprWrap{},
+	&cir.ExprJoin{},
+	&cir.ExprNew{},
+	&cir.ErrorTypeIsCheck{},
+	&cir.ErrorTypeIsHelperCheck{},
+	&cir.ErrorValueIsNotNil{},
+	&cir.ErrorValueIsNil{},
+	&cir.ErrorValueEQ{},
+	&cir.ErrorValueNEQ{},
+	&cir.ErrorTypeExtract{},
+}
+
+var (
+	nodeKinds = map[reflect.Type]string{}
+	kindTypes = map[string]reflect.Type{}
+)
+
+func init() {
+	for _, node := range nodeTypes {
+		typ := reflect.TypeOf(node).Elem()
+		kind := snakeCase(typ.Name())
+		nodeKinds[typ] = kind
+		kindTypes[kind] = typ
+	}
+}
+
+var (
+	spanType      = reflect.TypeFor[cir.Span]()
+	referenceType = reflect.TypeFor[cir.Reference]()
+	logLevelType  = reflect.TypeFor[cir.LogLevel]()
+)
+
+// jsonProgram is the JSON representation of [CIRProgram]. Nodes are objects whose
+// "kind" field tells the node type, other fields are node fields in snake case:
+//
+//	{
+//	  "kind": "assign",
+//	  "dst": {"kind": "expr_var", "name": "err", "span": {…}},
+//	  "src": {"kind": "expr_call", "has_args": true, "ref": "\"os\".Open", "span": {…}},
+//	  "span": {"start": {"line": 10, "column": 2, "offset": 135}, "end": {…}}
+//	}
+//
+// References are encoded in the text form and log levels with their names.
+type jsonProgram struct {
+	Version   int            `json:"version"`
+	File      string         `json:"file"`
+	Package   string         `json:"package"`
+	Functions []jsonFunction `json:"functions"`
+}
+
+type jsonFunction struct {
+	Name   string            `json:"name"`
+	Result string            `json:"result,omitempty"`
+	Params []string          `json:"params,omitempty"`
+	Nodes  []json.RawMessage `json:"nodes"`
+}
+
+type jsonSpan struct {
+	Start jsonPosition `json:"start"`
+	End   jsonPosition `json:"end"`
+}
+
+// jsonPosition is a position within the program file. The offset keeps decoded
+// positions exactly the same as encoded ones.
+type jsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Offset int `json:"offset"`
+}
+
+var _ json.Marshaler = (*CIRProgram)(nil)
+
+// MarshalJSON encodes the program into the versioned JSON form.
+func (p *CIRProgram) MarshalJSON() ([]byte, error) {
+	res := jsonProgram{
+		Version:   JSONVersion,
+		File:      p.File,
+		Package:   p.Package,
+		Functions: []jsonFunction{},
+	}
+
+	enc := &jsonEncoder{prog: p}
+	for _, fn := range p.Functions {
+		f := jsonFunction{
+			Name:   fn.Name,
+			Result: fn.Result,
+			Params: fn.Params,
+			Nodes:  []json.RawMessage{},
+		}
+		for _, node := range fn.Nodes {
+			v, err := enc.node(node)
+			if err != nil {
+				return nil, fmt.Errorf("encode function %s: %w", fn.Name, err)
+			}
+
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("marshal node of function %s: %w", fn.Name, err)
+			}
+			f.Nodes = append(f.Nodes, data)
+		}
+
+		res.Functions = append(res.Functions, f)
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		return nil, fmt.Errorf("marshal program: %w", err)
+	}
+
+	return data, nil
+}
+
+var _ json.Unmarshaler = (*CIRProgram)(nil)
+
+// UnmarshalJSON decodes the program from the JSON form made with [CIRProgram.MarshalJSON].
+func (p *CIRProgram) UnmarshalJSON(data []byte) error {
+	var src jsonProgram
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&src); err != nil {
+		return fmt.Errorf("decode program: %w", err)
+	}
+	if src.Version != JSONVersion {
+		return fmt.Errorf("unsupported CIR version %d, only %d is supported", src.Version, JSONVersion)
+	}
+
+	var d jsonDecoder
+	res := CIRProgram{
+		File:    src.File,
+		Package: src.Package,
+	}
+	for _, fn := range src.Functions {
+		f := CIRFunction{
+			Name:   fn.Name,
+			Result: fn.Result,
+			Params: fn.Params,
+		}
+		for i, raw := range fn.Nodes {
+			node, err := d.node(raw)
+			if err != nil {
+				return fmt.Errorf("decode function %s node #%d: %w", fn.Name, i, err)
+			}
+
+			f.Nodes = append(f.Nodes, node)
+		}
+
+		res.Functions = append(res.Functions, f)
+	}
+
+	if len(d.spans) > 0 {
+		if err := d.setSpans(&res); err != nil {
+			return fmt.Errorf("decode positions: %w", err)
+		}
+	}
+
+	*p = res
+	return nil
+}
+
+type jsonEncoder struct {
+	prog *CIRProgram
+}
+
+func (e *jsonEncoder) node(node cir.Node) (map[string]any, error) {
+	v := reflect.ValueOf(node).Elem()
+	kind, ok := nodeKinds[v.Type()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported node type %T", node)
+	}
+
+	res, err := e.fields(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", kind, err)
+	}
+	res["kind"] = kind
+
+	return res, nil
+}
+
+func (e *jsonEncoder) fields(v reflect.Value) (map[string]any, error) {
+	res := map[string]any{}
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if field.Type == spanType {
+			span, err := e.span(v.Field(i).Interface().(cir.Span))
+			if err != nil {
+				return nil, err
+			}
+			if span != nil {
+				res["span"] = span
+			}
+			continue
+		}
+
+		value, err := e.value(v.Field(i))
+		if err != nil {
+			return nil, fmt.Errorf("encode field %s: %w", field.Name, err)
+		}
+		res[snakeCase(field.Name)] = value
+	}
+
+	return res, nil
+}
+
+func (e *jsonEncoder) value(v reflect.Value) (any, error) {
+	switch {
+	case v.Type() == referenceType:
+		ref := v.Interface().(cir.Reference)
+		if ref == (cir.Reference{}) {
+			return nil, nil
+		}
+
+		data, err := tracing.Reference(ref).MarshalText()
+		if err != nil {
+			return nil, fmt.Errorf("encode reference: %w", err)
+		}
+		return string(data), nil
+
+	case v.Type() == logLevelType:
+		return v.Interface().(cir.LogLevel).String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+
+		node, ok := v.Interface().(cir.Node)
+		if !ok {
+			return nil, fmt.Errorf("unsupported value of type %s", v.Type())
+		}
+		return e.node(node)
+
+	case reflect.Slice:
+		res := make([]any, v.Len())
+		for i := range v.Len() {
+			item, err := e.value(v.Index(i))
+			if err != nil {
+				return nil, fmt.Errorf("encode item #%d: %w", i, err)
+			}
+			res[i] = item
+		}
+		return res, nil
+
+	case reflect.Struct:
+		return e.fields(v)
+
+	default:
+		return v.Interface(), nil
+	}
+}
+
+func (e *jsonEncoder) span(span cir.Span) (*jsonSpan, error) {
+	if e.prog.fset == nil || !span.Pos().IsValid() {
+		return nil, nil
+	}
+
+	start, err := e.position(span.Pos())
+	if err != nil {
+		return nil, err
+	}
+	end, err := e.position(span.End())
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsonSpan{Start: start, End: end}, nil
+}
+
+func (e *jsonEncoder) position(pos token.Pos) (jsonPosition, error) {
+	position := e.prog.fset.PositionFor(pos, false)
+	if position.Filename != e.prog.File {
+		return jsonPosition{}, fmt.Errorf("position %s is out of the file %s", position, e.prog.File)
+	}
+
+	return jsonPosition{
+		Line:   position.Line,
+		Column: position.Column,
+		Offset: position.Offset,
+	}, nil
+}
+
+type jsonDecoder struct {
+	spans []decodedSpan
+}
+
+// decodedSpan is a span whose positions can only be set when all of them are known.
+type decodedSpan struct {
+	node cir.Node
+	span jsonSpan
+}
+
+func (d *jsonDecoder) node(data []byte) (cir.Node, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("decode node object: %w", err)
+	}
+
+	var kind string
+	if err := json.Unmarshal(fields["kind"], &kind); err != nil {
+		return nil, fmt.Errorf("decode node kind: %w", err)
+	}
+	typ, ok := kindTypes[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown node kind %q", kind)
+	}
+	delete(fields, "kind")
+
+	res := reflect.New(typ)
+	node := res.Interface().(cir.Node)
+	if raw, ok := fields["span"]; ok {
+		var span jsonSpan
+		if err := json.Unmarshal(raw, &span); err != nil {
+			return nil, fmt.Errorf("decode %s span: %w", kind, err)
+		}
+
+		d.spans = append(d.spans, decodedSpan{node: node, span: span})
+		delete(fields, "span")
+	}
+
+	if err := d.fields(res.Elem(), fields); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", kind, err)
+	}
+
+	return node, nil
+}
+
+func (d *jsonDecoder) fields(v reflect.Value, fields map[string]json.RawMessage) error {
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if field.Type == spanType {
+			continue
+		}
+
+		name := snakeCase(field.Name)
+		raw, ok := fields[name]
+		if !ok {
+			continue
+		}
+		delete(fields, name)
+
+		if err := d.value(v.Field(i), raw); err != nil {
+			return fmt.Errorf("decode field %s: %w", field.Name, err)
+		}
+	}
+
+	for name := range fields {
+		return fmt.Errorf("unknown field %q", name)
+	}
+
+	return nil
+}
+
+func (d *jsonDecoder) value(v reflect.Value, data json.RawMessage) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	switch v.Type() {
+	case referenceType:
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return fmt.Errorf("decode reference: %w", err)
+		}
+
+		var ref tracing.Reference
+		if err := ref.UnmarshalText([]byte(text)); err != nil {
+			return fmt.Errorf("parse reference %q: %w", text, err)
+		}
+		v.Set(reflect.ValueOf(ref.CIR()))
+		return nil
+
+	case logLevelType:
+		var name string
+		if err := json.Unmarshal(data, &name); err != nil {
+			return fmt.Errorf("decode log level: %w", err)
+		}
+
+		for _, level := range []cir.LogLevel{cir.LogLevelWarn, cir.LogLevelError, cir.LogLevelFatal} {
+			if level.String() == name {
+				v.Set(reflect.ValueOf(level))
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown log level %q", name)
+	}
+
+	switch v.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		node, err := d.node(data)
+		if err != nil {
+			return fmt.Errorf("decode node of %s: %w", v.Type(), err)
+		}
+
+		nv := reflect.ValueOf(node)
+		if !nv.Type().AssignableTo(v.Type()) {
+			return fmt.Errorf("%T cannot be used as %s", node, v.Type())
+		}
+		v.Set(nv)
+		return nil
+
+	case reflect.Slice:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return fmt.Errorf("decode items: %w", err)
+		}
+		if len(items) == 0 {
+			return nil
+		}
+
+		res := reflect.MakeSlice(v.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := d.value(res.Index(i), item); err != nil {
+				return fmt.Errorf("decode item #%d: %w", i, err)
+			}
+		}
+		v.Set(res)
+		return nil
+
+	case reflect.Struct:
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return fmt.Errorf("decode %s object: %w", v.Type(), err)
+		}
+		return d.fields(v, fields)
+
+	default:
+		if err := json.Unmarshal(data, v.Addr().Interface()); err != nil {
+			return fmt.Errorf("decode %s value: %w", v.Kind(), err)
+		}
+		return nil
+	}
+}
+
+// setSpans restores positions of decoded nodes. A file is made for them with lines
+// that exactly match positions met.
+func (d *jsonDecoder) setSpans(p *CIRProgram) error {
+	lines := map[int]int{1: 0}
+	var size int
+	for _, s := range d.spans {
+		for _, pos := range []jsonPosition{s.span.Start, s.span.End} {
+			if pos.Line < 1 || pos.Column < 1 || pos.Offset < 0 {
+				return fmt.Errorf("invalid position %d:%d", pos.Line, pos.Column)
+			}
+
+			start := pos.Offset - pos.Column + 1
+			if prev, ok := lines[pos.Line]; ok && prev != start {
+				return fmt.Errorf("inconsistent position %d:%d", pos.Line, pos.Column)
+			}
+			lines[pos.Line] = start
+			size = max(size, pos.Offset+1)
+		}
+	}
+
+	// Lines having no positions are placed right before the next known line, each
+	// one is at least a newline long.
+	known := make([]int, 0, len(lines))
+	for line := range lines {
+		known = append(known, line)
+	}
+	slices.Sort(known)
+
+	offsets := make([]int, 0, known[len(known)-1])
+	for i, line := range known {
+		if i > 0 {
+			next := lines[line]
+			for missing := known[i-1] + 1; missing < line; missing++ {
+				offsets = append(offsets, next-(line-missing))
+			}
+		}
+		offsets = append(offsets, lines[line])
+	}
+
+	fset := token.NewFileSet()
+	file := fset.AddFile(p.File, -1, size)
+	if !file.SetLines(offsets) {
+		return errors.New("positions do not make valid lines")
+	}
+
+	for _, s := range d.spans {
+		s.node.SetSpan(file.Pos(s.span.Start.Offset), file.Pos(s.span.End.Offset))
+	}
+	p.fset = fset
+
+	return nil
+}
+
+// snakeCase turns Go names into snake case: "HasArgs" is "has_args", "RHS" is "rhs".
+func snakeCase(name string) string {
+	var buf strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (nextLower && unicode.IsUpper(runes[i-1])) {
+				buf.WriteByte('_')
+			}
+		}
+		buf.WriteRune(unicode.ToLower(r))
+	}
+
+	return buf.String()
+}
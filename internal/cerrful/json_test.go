@@ -0,0 +1,185 @@
+package cerrful
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirkon/cerrful/internal/cir"
+)
+
+func TestCIRProgramJSON(t *testing.T) {
+	const src = `package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+)
+
+var errNotFound = errors.New("not found")
+
+type file struct{}
+
+func (file) read(name string) (data []byte, err error) {
+	f, err := os.Open(name)
+	if err != nil {
+		log.Printf("open file: %v", err)
+		return
+	}
+	_ = f.Close()
+
+	switch e := err.(type) {
+	case *fs.PathError:
+		return nil, fmt.Errorf("path %s: %w", e.Path, err)
+	}
+
+	if errors.Is(err, io.EOF) {
+		return nil, errNotFound
+	} else if v, ok := err.(interface{ Timeout() bool }); ok && v.Timeout() {
+		return nil, err
+	}
+
+	return nil, io.ErrUnexpectedEOF
+}
+`
+
+	prog, err := DemoTranslate(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Log("\n" + prog.Pretty(true))
+
+	data, err := json.Marshal(prog)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got CIRProgram
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.File != prog.File || got.Package != prog.Package {
+		t.Errorf("unexpected program %s of %s", got.File, got.Package)
+	}
+	if !reflect.DeepEqual(got.Functions, prog.Functions) {
+		t.Errorf("program differs after decoding\n got:\n%s\nwant:\n%s", got.Pretty(true), prog.Pretty(true))
+	}
+
+	var nodes int
+	for i, fn := range prog.Functions {
+		for j, node := range fn.Nodes {
+			want := prog.Position(node)
+			if have := got.Position(got.Functions[i].Nodes[j]); have != want {
+				t.Errorf("unexpected position of %T: got %s, want %s", node, have, want)
+			}
+			cir.Inspect(node, func(cir.Node) bool {
+				nodes++
+				return true
+			})
+		}
+	}
+	if nodes == 0 {
+		t.Error("no nodes translated")
+	}
+}
+
+func TestCIRProgramJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{
+			name: "version",
+			data: `{"version": 18, "file": "a.go", "package": "main", "functions": []}`,
+			err:  "unsupported CIR version 18",
+		},
+		{
+			name: "kind",
+			data: `{"version": 21, "file": "a.go", "package": "main", "functions": [{"name": "f", "nodes": [{"kind": "goto"}]}]}`,
+			err:  `unknown node kind "goto"`,
+		},
+		{
+			name: "field",
+			data: `{"version": 21, "file": "a.go", "package": "main", "functions": [{"name": "f", "nodes": [{"kind": "return", "label": "x"}]}]}`,
+			err:  `unknown field "label"`,
+		},
+		{
+			name: "variant",
+			data: `{"version": 21, "file": "a.go", "package": "main", "functions": [{"name": "f", "nodes": [{"kind": "return", "var": {"kind": "expr_nil"}}]}]}`,
+			err:  "*cir.ExprNil cannot be used as *cir.ExprVar",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p CIRProgram
+			err := json.Unmarshal([]byte(tt.data), &p)
+			if err == nil {
+				t.Fatal("error expected")
+			}
+			if !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("unexpected error %q, want %q", err, tt.err)
+			}
+		})
+	}
+}
+
+// TestJSONSchema keeps names and types of node fields in the JSON encoding from changing
+// unnoticed: they come from names of fields of CIR types. Changes of the schema need a bump
+// of JSONVersion along with the update of the golden file.
+func TestJSONSchema(t *testing.T) {
+	var buf strings.Builder
+	fmt.Fprintf(&buf, "version %d\n", JSONVersion)
+	for _, node := range nodeTypes {
+		typ := reflect.TypeOf(node).Elem()
+		fmt.Fprintf(&buf, "%s %s\n", nodeKinds[typ], jsonSchemaOf(typ))
+	}
+
+	want, err := os.ReadFile(filepath.Join("testdata", "schema.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != string(want) {
+		t.Errorf("JSON schema changed, bump JSONVersion and update the golden file:\n%s", got)
+	}
+}
+
+func jsonSchemaOf(typ reflect.Type) string {
+	switch typ {
+	case referenceType:
+		return "reference"
+	case logLevelType:
+		return "level"
+	}
+
+	switch typ.Kind() {
+	case reflect.Interface, reflect.Pointer:
+		return "node"
+	case reflect.Slice:
+		return "[]" + jsonSchemaOf(typ.Elem())
+	case reflect.Struct:
+		var fields []string
+		for i := range typ.NumField() {
+			field := typ.Field(i)
+			if field.Type == spanType {
+				fields = append(fields, "span")
+				continue
+			}
+			fields = append(fields, snakeCase(field.Name)+": "+jsonSchemaOf(field.Type))
+		}
+		return "{" + strings.Join(fields, ", ") + "}"
+	default:
+		return typ.Kind().String()
+	}
+}
@@ -0,0 +1,268 @@
+package cerrful
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/sirkon/cerrful/internal/cir"
+)
+
+// Pretty renders the program in a human-readable form. Nested blocks are shown
+// with indentation if indented is set and with curly braces otherwise:
+//
+//	Function foo:                                 Function foo {
+//	  Assign [err] <- os.Open(…) (foreign call)     Assign [err] <- os.Open(…) (foreign call)
+//	                                              }
+func (p *CIRProgram) Pretty(indented bool) string {
+	pp := &printer{
+		pkg:      p.Package,
+		indented: indented,
+	}
+	for i := range p.Functions {
+		if i > 0 {
+			pp.buf.WriteByte('\n')
+		}
+		pp.function(&p.Functions[i])
+	}
+
+	return pp.buf.String()
+}
+
+type printer struct {
+	buf      strings.Builder
+	pkg      string
+	indented bool
+	depth    int
+}
+
+func (p *printer) function(fn *CIRFunction) {
+	p.open("Function " + fn.Name)
+	p.nodes(fn.Nodes)
+	p.close()
+}
+
+func (p *printer) nodes(nodes []Node) {
+	for _, node := range nodes {
+		p.node(node)
+	}
+}
+
+func (p *printer) node(node Node) {
+	switch v := node.(type) {
+	case *cir.Assign:
+		p.assign(v)
+
+	case *cir.AssignAssert:
+		dst := p.dst(v.Dst)
+		if v.Guard != "" {
+			dst += ", " + v.Guard
+		}
+		p.line("Assign [%s] <- %s.(%s) (type assertion)", dst, p.expr(v.Src), p.ref(v.Type))
+
+	case *cir.AssignCheckFlag:
+		p.line("Assign [%s] <- %s", v.Dst, p.expr(v.Src))
+
+	case *cir.Return:
+		if v.Bare {
+			p.line("Return [%s] (bare)", p.expr(v.Var))
+		} else {
+			p.line("Return [%s]", p.expr(v.Var))
+		}
+
+	case *cir.If:
+		p.open("If " + strconv.Quote(v.Cond))
+		p.nodes(v.Body)
+		p.close()
+		if len(v.Else) > 0 {
+			p.open("Else")
+			p.nodes(v.Else)
+			p.close()
+		}
+
+	case *cir.Switch:
+		header := "Switch"
+		if v.Tag != "" {
+			header += " " + strconv.Quote(v.Tag)
+		}
+		p.open(header)
+		for _, c := range v.Cases {
+			if len(c.List) == 0 {
+				p.open("Default")
+			} else {
+				list := make([]string, len(c.List))
+				for i, item := range c.List {
+					list[i] = strconv.Quote(item)
+				}
+				p.open("Case " + strings.Join(list, ", "))
+			}
+			p.nodes(c.Body)
+			p.close()
+		}
+		p.close()
+
+	case *cir.Log:
+		var msg string
+		if v.Msg != "" {
+			msg = " msg=" + strconv.Quote(v.Msg)
+		}
+		p.line("Log [%s] level=%s%s (via %s)", p.expr(v.Var), v.Level, msg, p.ref(v.Ref))
+
+	default:
+		p.line("%s", p.expr(node))
+	}
+}
+
+// assign prints an assignment. Wraps are shown as the assignment of the wrapped error
+// followed by the wrap itself, so the same variable carries the error through. The
+// wrapped error is shown as "?" if it is not a variable.
+func (p *printer) assign(a *cir.Assign) {
+	dst := p.dst(a.Dst)
+	switch v := a.Src.(type) {
+	case *cir.ExprWrap:
+		src := "?"
+		if v.Var != nil && v.Var.Name != "" {
+			src = v.Var.Name
+		}
+		p.line("Assign [%s] <- %s", dst, src)
+		p.line("Wrap [%s] msg=%s (via %s)%s", dst, strconv.Quote(v.Msg), p.ref(v.Ref), opaque(v.Opaque))
+
+	default:
+		p.line("Assign [%s] <- %s", dst, p.expr(a.Src))
+	}
+}
+
+func (p *printer) expr(node any) string {
+	switch v := node.(type) {
+	case nil:
+		return "?"
+	case *cir.ExprVar:
+		if v == nil {
+			return "?"
+		}
+		return v.Name
+	case *cir.ExprVarHidden:
+		return "_"
+	case *cir.ExprNil:
+		return "nil"
+	case *cir.ExprAlias:
+		return v.Target
+	case *cir.ExprSentinel:
+		return p.localRef(v.Ref) + " (" + p.locality(v.Ref) + " sentinel)"
+	case *cir.ExprType:
+		return p.localRef(v.Ref) + "{…} (" + p.locality(v.Ref) + " type)"
+	case *cir.ExprCall:
+		args := "()"
+		if v.HasArgs {
+			args = "(…)"
+		}
+		return p.localRef(v.Ref) + args + " (" + p.locality(v.Ref) + " call)"
+	case *cir.ExprNew:
+		return "NewError msg=" + strconv.Quote(v.Msg) + " (via " + p.ref(v.Ref) + ")"
+	case *cir.ExprJoin:
+		vars := make([]string, len(v.Vars))
+		for i, x := range v.Vars {
+			vars[i] = "?"
+			if x != nil && x.Name != "" {
+				vars[i] = x.Name
+			}
+		}
+		var msg string
+		if v.Msg != "" {
+			msg = " msg=" + strconv.Quote(v.Msg)
+		}
+		return "Join [" + strings.Join(vars, ", ") + "]" + msg + " (via " + p.ref(v.Ref) + ")" + opaque(v.Opaque)
+	case *cir.ExprWrap:
+		var src string
+		if v.Var != nil && v.Var.Name != "" {
+			src = " [" + v.Var.Name + "]"
+		}
+		return "Wrap" + src + " msg=" + strconv.Quote(v.Msg) + " (via " + p.ref(v.Ref) + ")" + opaque(v.Opaque)
+	default:
+		return fmt.Sprintf("%T", node)
+	}
+}
+
+// opaqueMark marks wraps keeping the text of errors only.
+const opaqueMark = " (opaque)"
+
+func opaque(v bool) string {
+	if v {
+		return opaqueMark
+	}
+
+	return ""
+}
+
+func (p *printer) dst(dst cir.ErrorVarNode) string {
+	return p.expr(dst)
+}
+
+// ref renders a reference in a shorthand form: "pkg/path.Type.Name". The package
+// path is quoted if its last element has dots, like "gopkg.in/yaml.v3".Unmarshal.
+// References to builtins are shown without the package.
+func (p *printer) ref(ref cir.Reference) string {
+	return p.refWith(ref, ref.Package != builtinPackage)
+}
+
+// localRef renders a reference like ref does, but references to local entities are
+// shown without the package as well. It is used where the locality is shown explicitly.
+func (p *printer) localRef(ref cir.Reference) string {
+	return p.refWith(ref, ref.Package != builtinPackage && ref.Package != p.pkg)
+}
+
+func (p *printer) refWith(ref cir.Reference, qualified bool) string {
+	if ref == (cir.Reference{}) {
+		return "?"
+	}
+
+	var buf strings.Builder
+	if qualified {
+		if strings.Contains(path.Base(ref.Package), ".") {
+			buf.WriteString(strconv.Quote(ref.Package))
+		} else {
+			buf.WriteString(ref.Package)
+		}
+		buf.WriteByte('.')
+	}
+	if ref.Type != "" {
+		buf.WriteString(ref.Type)
+		buf.WriteByte('.')
+	}
+	buf.WriteString(ref.Name)
+
+	return buf.String()
+}
+
+func (p *printer) locality(ref cir.Reference) string {
+	if ref.Package == p.pkg {
+		return "local"
+	}
+
+	return "foreign"
+}
+
+func (p *printer) open(header string) {
+	if p.indented {
+		p.line("%s:", header)
+	} else {
+		p.line("%s {", header)
+	}
+	p.depth++
+}
+
+func (p *printer) close() {
+	p.depth--
+	if !p.indented {
+		p.line("}")
+	}
+}
+
+func (p *printer) line(format string, a ...any) {
+	for range p.depth {
+		p.buf.WriteString("  ")
+	}
+	fmt.Fprintf(&p.buf, format, a...)
+	p.buf.WriteByte('\n')
+}
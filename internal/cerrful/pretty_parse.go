@@ -0,0 +1,641 @@
+package cerrful
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strconv"
+	"strings"
+
+	"github.com/sirkon/cerrful/internal/cir"
+	"github.com/sirkon/cerrful/internal/tracing"
+)
+
+// ParsePretty parses a program rendered by [CIRProgram.Pretty], both indented and
+// curly forms are supported. References to local entities are resolved with the
+// given package path. The text form has no positions, so parsed nodes have none.
+func ParsePretty(pkg, text string) (*CIRProgram, error) {
+	p := &prettyParser{pkg: pkg}
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line == "" {
+			continue
+		}
+
+		body := strings.TrimLeft(line, " ")
+		p.lines = append(p.lines, prettyLine{
+			no:     i + 1,
+			indent: len(line) - len(body),
+			text:   body,
+		})
+	}
+
+	res := &CIRProgram{Package: pkg}
+	for p.pos < len(p.lines) {
+		fn, err := p.function()
+		if err != nil {
+			return nil, err
+		}
+
+		res.Functions = append(res.Functions, *fn)
+	}
+
+	return res, nil
+}
+
+type prettyLine struct {
+	no     int
+	indent int
+	text   string
+}
+
+type prettyParser struct {
+	pkg   string
+	lines []prettyLine
+	pos   int
+}
+
+func (p *prettyParser) function() (*CIRFunction, error) {
+	line := p.lines[p.pos]
+	header, curly, ok := blockHeader(line.text)
+	name, found := strings.CutPrefix(header, "Function ")
+	if !ok || !found || name == "" {
+		return nil, p.errorf(line, "function header expected")
+	}
+
+	p.pos++
+	nodes, err := p.block(line, curly)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CIRFunction{Name: name, Nodes: nodes}, nil
+}
+
+// block parses nodes of the block started by the header line.
+func (p *prettyParser) block(header prettyLine, curly bool) ([]Node, error) {
+	var res []Node
+	indent := -1
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent <= header.indent {
+			break
+		}
+		if indent < 0 {
+			indent = line.indent
+		}
+		if line.indent != indent {
+			return nil, p.errorf(line, "unexpected indentation")
+		}
+
+		node, err := p.node(line, res)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			res = append(res, node)
+		}
+	}
+
+	if !curly {
+		return res, nil
+	}
+
+	if p.pos >= len(p.lines) || p.lines[p.pos].text != "}" || p.lines[p.pos].indent != header.indent {
+		return nil, p.errorf(header, "block is not closed")
+	}
+	p.pos++
+
+	return res, nil
+}
+
+// node parses the node at the current line. Previous nodes of the same block are
+// given to attach else branches to. Returns nil node if nothing is to be added.
+func (p *prettyParser) node(line prettyLine, prev []Node) (Node, error) {
+	if header, curly, ok := blockHeader(line.text); ok {
+		p.pos++
+		return p.blockNode(line, header, curly, prev)
+	}
+
+	kind, _, _ := strings.Cut(line.text, " ")
+	p.pos++
+	switch kind {
+	case "Assign":
+		return p.assign(line)
+	case "Log":
+		return p.log(line)
+	case "Return":
+		return p.ret(line)
+	case "Wrap":
+		return nil, p.errorf(line, "wrap must follow the assignment of the wrapped error")
+	default:
+		return nil, p.errorf(line, "unexpected %q", line.text)
+	}
+}
+
+func (p *prettyParser) blockNode(line prettyLine, header string, curly bool, prev []Node) (Node, error) {
+	kind, arg, _ := strings.Cut(header, " ")
+	switch kind {
+	case "If":
+		cond, err := unquoteAll(arg)
+		if err != nil {
+			return nil, p.errorf(line, "parse condition: %w", err)
+		}
+		body, err := p.block(line, curly)
+		if err != nil {
+			return nil, err
+		}
+
+		return &cir.If{Cond: cond, Body: body}, nil
+
+	case "Else":
+		var last *cir.If
+		if len(prev) > 0 {
+			last, _ = prev[len(prev)-1].(*cir.If)
+		}
+		if last == nil || last.Else != nil || arg != "" {
+			return nil, p.errorf(line, "else must follow an if")
+		}
+		body, err := p.block(line, curly)
+		if err != nil {
+			return nil, err
+		}
+		if len(body) == 0 {
+			return nil, p.errorf(line, "empty else")
+		}
+
+		last.Else = body
+		return nil, nil
+
+	case "Switch":
+		res := &cir.Switch{}
+		if arg != "" {
+			tag, err := unquoteAll(arg)
+			if err != nil {
+				return nil, p.errorf(line, "parse tag: %w", err)
+			}
+			res.Tag = tag
+		}
+
+		cases, err := p.cases(line, curly)
+		if err != nil {
+			return nil, err
+		}
+		res.Cases = cases
+		return res, nil
+
+	default:
+		return nil, p.errorf(line, "unexpected %s block", kind)
+	}
+}
+
+func (p *prettyParser) cases(header prettyLine, curly bool) ([]cir.SwitchCase, error) {
+	var res []cir.SwitchCase
+	for p.pos < len(p.lines) && p.lines[p.pos].indent > header.indent {
+		line := p.lines[p.pos]
+		text, clauseCurly, ok := blockHeader(line.text)
+		if !ok {
+			return nil, p.errorf(line, "switch clause expected")
+		}
+		p.pos++
+
+		var c cir.SwitchCase
+		switch kind, arg, _ := strings.Cut(text, " "); kind {
+		case "Case":
+			for arg != "" {
+				item, err := strconv.QuotedPrefix(arg)
+				if err != nil {
+					return nil, p.errorf(line, "parse clause: %w", err)
+				}
+				value, err := strconv.Unquote(item)
+				if err != nil {
+					return nil, p.errorf(line, "unquote clause %s: %w", item, err)
+				}
+				c.List = append(c.List, value)
+
+				arg = strings.TrimPrefix(arg[len(item):], ", ")
+			}
+			if len(c.List) == 0 {
+				return nil, p.errorf(line, "empty clause")
+			}
+		case "Default":
+		default:
+			return nil, p.errorf(line, "switch clause expected")
+		}
+
+		body, err := p.block(line, clauseCurly)
+		if err != nil {
+			return nil, err
+		}
+		c.Body = body
+		res = append(res, c)
+	}
+
+	if !curly {
+		return res, nil
+	}
+	if p.pos >= len(p.lines) || p.lines[p.pos].text != "}" || p.lines[p.pos].indent != header.indent {
+		return nil, p.errorf(header, "block is not closed")
+	}
+	p.pos++
+
+	return res, nil
+}
+
+// assign parses assignments. Assignments followed by wraps of the same variable make
+// assignments of wraps:
+//
+//	Assign [@err] <- err
+//	Wrap [@err] msg="read file" (via fmt.Errorf)
+func (p *prettyParser) assign(line prettyLine) (Node, error) {
+	dst, rest, err := bracketed(strings.TrimPrefix(line.text, "Assign "))
+	if err != nil {
+		return nil, p.errorf(line, "parse destination: %w", err)
+	}
+	src, ok := strings.CutPrefix(rest, " <- ")
+	if !ok {
+		return nil, p.errorf(line, "source expected")
+	}
+
+	if body, ok := strings.CutSuffix(src, " (type assertion)"); ok {
+		return p.assert(line, dst, body)
+	}
+
+	v, err := errorVarNode(dst)
+	if err != nil {
+		return nil, p.errorf(line, "%w", err)
+	}
+
+	if src == "?" || isPrettyIdent(src) {
+		wrap, err := p.wrap(dst, src)
+		if err != nil {
+			return nil, err
+		}
+		if wrap != nil {
+			return &cir.Assign{Dst: v, Src: wrap}, nil
+		}
+	}
+
+	expr, err := p.expr(src)
+	if err != nil {
+		return nil, p.errorf(line, "%w", err)
+	}
+
+	return &cir.Assign{Dst: v, Src: expr}, nil
+}
+
+// wrap parses the wrap following the assignment of the wrapped error, if there is one.
+func (p *prettyParser) wrap(dst, src string) (*cir.ExprWrap, error) {
+	if p.pos >= len(p.lines) {
+		return nil, nil
+	}
+	line := p.lines[p.pos]
+	rest, ok := strings.CutPrefix(line.text, "Wrap ["+dst+"] ")
+	if !ok || line.indent != p.lines[p.pos-1].indent {
+		return nil, nil
+	}
+	p.pos++
+
+	res := &cir.ExprWrap{Var: &cir.ExprVar{}}
+	if src != "?" {
+		res.Var.Name = src
+	}
+
+	rest, ok = strings.CutPrefix(rest, "msg=")
+	if !ok {
+		return nil, p.errorf(line, "wrap message expected")
+	}
+	msg, rest, err := quoted(rest)
+	if err != nil {
+		return nil, p.errorf(line, "parse message: %w", err)
+	}
+	res.Msg = msg
+
+	rest, res.Opaque = strings.CutSuffix(rest, opaqueMark)
+	if res.Ref, err = p.via(rest); err != nil {
+		return nil, p.errorf(line, "%w", err)
+	}
+
+	return res, nil
+}
+
+func (p *prettyParser) assert(line prettyLine, dst, body string) (Node, error) {
+	res := &cir.AssignAssert{}
+	dst, guard, ok := strings.Cut(dst, ", ")
+	if ok {
+		res.Guard = guard
+	}
+
+	v, err := errorVarNode(dst)
+	if err != nil {
+		return nil, p.errorf(line, "%w", err)
+	}
+	res.Dst = v
+
+	src, typ, ok := strings.Cut(body, ".(")
+	typ, found := strings.CutSuffix(typ, ")")
+	if !ok || !found || !isPrettyIdent(src) {
+		return nil, p.errorf(line, "type assertion expected")
+	}
+	res.Src = &cir.ExprVar{Name: src}
+
+	if res.Type, err = p.ref(typ, false); err != nil {
+		return nil, p.errorf(line, "parse asserted type: %w", err)
+	}
+
+	return res, nil
+}
+
+func (p *prettyParser) expr(src string) (cir.Expr, error) {
+	if src == "nil" {
+		return &cir.ExprNil{}, nil
+	}
+	if isPrettyIdent(src) {
+		return &cir.ExprAlias{Target: src}, nil
+	}
+
+	if rest, ok := strings.CutPrefix(src, "NewError msg="); ok {
+		msg, rest, err := quoted(rest)
+		if err != nil {
+			return nil, fmt.Errorf("parse error message: %w", err)
+		}
+		ref, err := p.via(rest)
+		if err != nil {
+			return nil, err
+		}
+
+		return &cir.ExprNew{Msg: msg, Ref: ref}, nil
+	}
+
+	if rest, ok := strings.CutPrefix(src, "Join "); ok {
+		return p.join(rest)
+	}
+
+	start := strings.LastIndex(src, " (")
+	if start < 0 || !strings.HasSuffix(src, ")") {
+		return nil, fmt.Errorf("unexpected source %q", src)
+	}
+	body, note := src[:start], src[start+2:len(src)-1]
+	locality, kind, _ := strings.Cut(note, " ")
+	if locality != "local" && locality != "foreign" {
+		return nil, fmt.Errorf("unexpected source %q", src)
+	}
+	local := locality == "local"
+
+	switch kind {
+	case "call":
+		res := &cir.ExprCall{}
+		if ref, ok := strings.CutSuffix(body, "(…)"); ok {
+			res.HasArgs = true
+			body = ref
+		} else if ref, ok := strings.CutSuffix(body, "()"); ok {
+			body = ref
+		} else {
+			return nil, fmt.Errorf("call expected in %q", src)
+		}
+
+		ref, err := p.ref(body, local)
+		if err != nil {
+			return nil, err
+		}
+		res.Ref = ref
+		return res, nil
+
+	case "sentinel":
+		ref, err := p.ref(body, local)
+		if err != nil {
+			return nil, err
+		}
+		return &cir.ExprSentinel{Ref: ref}, nil
+
+	case "type":
+		body, ok := strings.CutSuffix(body, "{…}")
+		if !ok {
+			return nil, fmt.Errorf("composite literal expected in %q", src)
+		}
+		ref, err := p.ref(body, local)
+		if err != nil {
+			return nil, err
+		}
+		return &cir.ExprType{Ref: ref}, nil
+
+	default:
+		return nil, fmt.Errorf("unexpected source %q", src)
+	}
+}
+
+// join parses joins following the "Join " prefix:
+//
+//	Join [err1, ?] msg="read, close" (via fmt.Errorf)
+//	Join [err1, err2] msg="read, close" (via fmt.Errorf) (opaque)
+func (p *prettyParser) join(src string) (*cir.ExprJoin, error) {
+	vars, rest, err := bracketed(src)
+	if err != nil {
+		return nil, fmt.Errorf("parse joined errors: %w", err)
+	}
+
+	res := &cir.ExprJoin{}
+	for _, name := range strings.Split(vars, ", ") {
+		switch {
+		case name == "?":
+			res.Vars = append(res.Vars, &cir.ExprVar{})
+		case isPrettyIdent(name):
+			res.Vars = append(res.Vars, &cir.ExprVar{Name: name})
+		default:
+			return nil, fmt.Errorf("unexpected joined error %q", name)
+		}
+	}
+
+	if msg, ok := strings.CutPrefix(rest, " msg="); ok {
+		if res.Msg, rest, err = quoted(msg); err != nil {
+			return nil, fmt.Errorf("parse join message: %w", err)
+		}
+	}
+
+	rest, res.Opaque = strings.CutSuffix(rest, opaqueMark)
+	if res.Ref, err = p.via(rest); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (p *prettyParser) log(line prettyLine) (Node, error) {
+	v, rest, err := bracketed(strings.TrimPrefix(line.text, "Log "))
+	if err != nil {
+		return nil, p.errorf(line, "parse logged error: %w", err)
+	}
+
+	res := &cir.Log{}
+	if v != "?" {
+		res.Var = &cir.ExprVar{Name: v}
+	}
+
+	rest, ok := strings.CutPrefix(rest, " level=")
+	if !ok {
+		return nil, p.errorf(line, "log level expected")
+	}
+	level, rest, _ := strings.Cut(rest, " ")
+	for _, l := range []cir.LogLevel{cir.LogLevelWarn, cir.LogLevelError, cir.LogLevelFatal} {
+		if l.String() == level {
+			res.Level = l
+		}
+	}
+	if res.Level == 0 {
+		return nil, p.errorf(line, "unknown log level %q", level)
+	}
+
+	if msg, ok := strings.CutPrefix(rest, "msg="); ok {
+		if res.Msg, rest, err = quoted(msg); err != nil {
+			return nil, p.errorf(line, "parse message: %w", err)
+		}
+	} else {
+		rest = " " + rest
+	}
+
+	if res.Ref, err = p.via(rest); err != nil {
+		return nil, p.errorf(line, "%w", err)
+	}
+
+	return res, nil
+}
+
+func (p *prettyParser) ret(line prettyLine) (Node, error) {
+	v, rest, err := bracketed(strings.TrimPrefix(line.text, "Return "))
+	if err != nil {
+		return nil, p.errorf(line, "parse returned error: %w", err)
+	}
+
+	res := &cir.Return{Var: &cir.ExprVar{Name: v}}
+	switch rest {
+	case "":
+	case " (bare)":
+		res.Bare = true
+	default:
+		return nil, p.errorf(line, "unexpected %q", rest)
+	}
+
+	return res, nil
+}
+
+// via parses " (via REF)".
+func (p *prettyParser) via(s string) (cir.Reference, error) {
+	s, ok := strings.CutPrefix(s, " (via ")
+	if ok {
+		s, ok = strings.CutSuffix(s, ")")
+	}
+	if !ok {
+		return cir.Reference{}, fmt.Errorf("(via …) expected")
+	}
+
+	return p.ref(s, false)
+}
+
+// ref parses references rendered by the printer. Local references have no package,
+// the only other references without packages are ones to builtins.
+func (p *prettyParser) ref(s string, local bool) (cir.Reference, error) {
+	switch {
+	case s == "?":
+		return cir.Reference{}, nil
+
+	case local:
+		typ, name, ok := strings.Cut(s, ".")
+		if !ok {
+			typ, name = "", typ
+		}
+		if (typ != "" && !isPrettyIdent(typ)) || !isPrettyIdent(name) {
+			return cir.Reference{}, fmt.Errorf("invalid local reference %q", s)
+		}
+		return cir.Reference{Package: p.pkg, Type: typ, Name: name}, nil
+
+	case isPrettyIdent(s):
+		return cir.Reference{Package: builtinPackage, Name: s}, nil
+	}
+
+	var ref tracing.Reference
+	if err := ref.UnmarshalText([]byte(s)); err != nil {
+		return cir.Reference{}, fmt.Errorf("parse reference: %w", err)
+	}
+
+	return ref.CIR(), nil
+}
+
+func (p *prettyParser) errorf(line prettyLine, format string, a ...any) error {
+	err := fmt.Errorf(format, a...)
+	return fmt.Errorf("line %d: %w", line.no, err)
+}
+
+// blockHeader checks if the text is a block header and returns it without the
+// block opening suffix.
+func blockHeader(text string) (header string, curly bool, ok bool) {
+	kind, _, _ := strings.Cut(text, " ")
+	switch strings.TrimSuffix(kind, ":") {
+	case "Function", "If", "Else", "Switch", "Case", "Default":
+	default:
+		return "", false, false
+	}
+
+	if header, ok := strings.CutSuffix(text, " {"); ok {
+		return header, true, true
+	}
+	if header, ok := strings.CutSuffix(text, ":"); ok {
+		return header, false, true
+	}
+
+	return "", false, false
+}
+
+func errorVarNode(name string) (cir.ErrorVarNode, error) {
+	switch {
+	case name == "_":
+		return &cir.ExprVarHidden{}, nil
+	case isPrettyIdent(name):
+		return &cir.ExprVar{Name: name}, nil
+	default:
+		return nil, fmt.Errorf("invalid variable %q", name)
+	}
+}
+
+// bracketed splits "[value] rest" into the value and the rest.
+func bracketed(s string) (string, string, error) {
+	if !strings.HasPrefix(s, "[") {
+		return "", "", errors.New("[…] expected")
+	}
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return "", "", errors.New("unterminated […]")
+	}
+
+	return s[1:end], s[end+1:], nil
+}
+
+// quoted splits a string starting with a quoted string into its value and the rest.
+func quoted(s string) (string, string, error) {
+	q, err := strconv.QuotedPrefix(s)
+	if err != nil {
+		return "", "", fmt.Errorf("look for quoted string: %w", err)
+	}
+	v, err := strconv.Unquote(q)
+	if err != nil {
+		return "", "", fmt.Errorf("unquote %s: %w", q, err)
+	}
+
+	return v, s[len(q):], nil
+}
+
+func unquoteAll(s string) (string, error) {
+	v, rest, err := quoted(s)
+	if err != nil {
+		return "", err
+	}
+	if rest != "" {
+		return "", fmt.Errorf("unexpected %q after the quoted string", rest)
+	}
+
+	return v, nil
+}
+
+// isPrettyIdent checks if the string is an identifier, the synthetic @err included.
+func isPrettyIdent(s string) bool {
+	return token.IsIdentifier(strings.TrimPrefix(s, "@"))
+}
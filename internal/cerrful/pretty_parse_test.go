@@ -0,0 +1,151 @@
+package cerrful
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParsePretty(t *testing.T) {
+	const src = `package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+)
+
+var errStop = errors.New("stop")
+
+type runner struct{}
+
+func (runner) run() error { return nil }
+
+func do(r runner) (err error) {
+	if err = r.run(); err != nil {
+		log.Println("run:", err)
+		return
+	}
+
+	_, err = os.Stat("file")
+	switch {
+	case errors.Is(err, fs.ErrNotExist), err == errStop:
+		return fmt.Errorf("stat file: %w", err)
+	}
+
+	pe, ok := err.(*fs.PathError)
+	if ok {
+		return pe
+	}
+
+	if err != nil {
+		return fmt.Errorf("stat %s: %w, stop: %w", "file", err, errStop)
+	}
+
+	return errors.Join(err, os.ErrClosed)
+}
+`
+
+	prog, err := DemoTranslate(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, indented := range []bool{true, false} {
+		text := prog.Pretty(indented)
+		got, err := ParsePretty(prog.Package, text)
+		if err != nil {
+			t.Fatalf("parse\n%s\n%s", text, err)
+		}
+
+		if diff := Diff(prog, got); diff != nil {
+			t.Errorf("unexpected differences in\n%s\n%s", text, strings.Join(diff, "\n"))
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	const want = `Function f:
+  Assign [err] <- os.Open(…) (foreign call)
+  If "err != nil":
+    Assign [@err] <- err
+    Wrap [@err] msg="open file" (via fmt.Errorf)
+    Return [@err]
+`
+	const got = `Function f {
+  Assign [err] <- os.Open(…) (foreign call)
+  If "err != nil" {
+    Assign [@err] <- err
+    Wrap [@err] msg="open" (via fmt.Errorf)
+    Return [@err]
+    Return [err]
+  }
+}
+`
+
+	w, err := ParsePretty("main", want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := ParsePretty("main", got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	diff := Diff(w, g)
+	expected := []string{
+		`f/1/body/0: want Assign [@err] <- err; Wrap [@err] msg="open file" (via fmt.Errorf), got Assign [@err] <- err; Wrap [@err] msg="open" (via fmt.Errorf)`,
+		`f/1/body/2: unexpected Return [err]`,
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("unexpected diff\n got: %q\nwant: %q", diff, expected)
+	}
+}
+
+func TestParsePrettyErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		err  string
+	}{
+		{
+			name: "header",
+			text: "Assign [err] <- nil",
+			err:  "line 1: function header expected",
+		},
+		{
+			name: "wrap",
+			text: "Function f:\n  Wrap [err] msg=\"do\" (via fmt.Errorf)",
+			err:  "line 2: wrap must follow the assignment of the wrapped error",
+		},
+		{
+			name: "unclosed",
+			text: "Function f {\n  Return [err]",
+			err:  "line 1: block is not closed",
+		},
+		{
+			name: "else",
+			text: "Function f:\n  Else:\n    Return [err]",
+			err:  "line 2: else must follow an if",
+		},
+		{
+			name: "level",
+			text: "Function f:\n  Log [err] level=info (via log.Println)",
+			err:  `line 2: unknown log level "info"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePretty("main", tt.text)
+			if err == nil {
+				t.Fatal("error expected")
+			}
+			if err.Error() != tt.err {
+				t.Errorf("unexpected error %q, want %q", err, tt.err)
+			}
+		})
+	}
+}
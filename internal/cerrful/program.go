@@ -0,0 +1,37 @@
+package cerrful
+
+import (
+	"go/token"
+
+	"github.com/sirkon/cerrful/internal/cir"
+)
+
+// CIRProgram represents CIR of a single Go source file.
+type CIRProgram struct {
+	// File is the name of the source file.
+	File string
+
+	// Package is the import path of the package the file belongs to. It tells
+	// local entities from foreign ones.
+	Package string
+
+	Functions []CIRFunction
+
+	fset *token.FileSet
+}
+
+// Position returns the source position of the node. It is invalid for nodes having
+// no positions and for programs made manually.
+func (p *CIRProgram) Position(node Node) token.Position {
+	if p.fset == nil || node == nil {
+		return token.Position{}
+	}
+
+	return p.fset.Position(node.Pos())
+}
+
+// CIRFunction represents CIR of a single Go function.
+type CIRFunction = cir.Function
+
+// Node is a single CIR node of a function.
+type Node = cir.Node
@@ -0,0 +1,604 @@
+package cerrful
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"sync"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/sirkon/cerrful/internal/cir"
+	"github.com/sirkon/cerrful/internal/config"
+	"github.com/sirkon/cerrful/internal/tracing"
+)
+
+// syntheticErrName is used for errors having no explicit variable, e.g. direct returns.
+const syntheticErrName = "@err"
+
+// demoPackage is the import path of the package DemoTranslate sources belong to.
+const demoPackage = "main"
+
+// DemoTranslate translates functions of the given Go source into CIR using built-in
+// configuration only. Other sources of the same package can be given to provide
+// declarations the source depends on, their functions are not translated.
+//
+// The sources can only import standard library packages.
+func DemoTranslate(src string, deps ...string) (*CIRProgram, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "demo.go", src, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, fmt.Errorf("parse source: %w", err)
+	}
+
+	files := []*ast.File{file}
+	for i, dep := range deps {
+		f, err := parser.ParseFile(fset, fmt.Sprintf("dep%d.go", i), dep, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("parse dependency source #%d: %w", i, err)
+		}
+
+		files = append(files, f)
+	}
+
+	info := &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Instances:  map[*ast.Ident]types.Instance{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Implicits:  map[ast.Node]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+		Scopes:     map[ast.Node]*types.Scope{},
+	}
+	conf := types.Config{Importer: stdImporter}
+	pkg, err := conf.Check(demoPackage, fset, files, info)
+	if err != nil {
+		return nil, fmt.Errorf("type check source: %w", err)
+	}
+
+	pass := &analysis.Pass{
+		Fset:      fset,
+		Files:     files,
+		Pkg:       pkg,
+		TypesInfo: info,
+	}
+	return Translate(pass, &config.Config{}, file), nil
+}
+
+// Translate translates functions of the file into CIR.
+func Translate(pass *analysis.Pass, cfg *config.Config, file *ast.File) *CIRProgram {
+	var reports tracing.ReportEngine
+	engine := tracing.NewScrapEngine(reports.Phase(tracing.ReportScrap))
+	cfg.Apply(engine)
+
+	ctx := tracing.NewContext()
+	engine.Scrap(ctx, pass, file)
+
+	res := &CIRProgram{
+		File:    pass.Fset.Position(file.Pos()).Filename,
+		Package: pass.Pkg.Path(),
+		fset:    pass.Fset,
+	}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Body == nil {
+			continue
+		}
+
+		// CIR describes functions returning errors only.
+		result := resultName(pass.TypesInfo, fn)
+		if result == "" {
+			continue
+		}
+
+		t := &translator{
+			info:   pass.TypesInfo,
+			ctx:    ctx,
+			result: result,
+		}
+		res.Functions = append(res.Functions, CIRFunction{
+			Name:   funcName(fn),
+			Result: t.result,
+			Params: errorParams(pass.TypesInfo, fn),
+			Nodes:  t.stmts(fn.Body.List),
+		})
+	}
+
+	return res
+}
+
+// translator translates function bodies into CIR nodes. Expressions are taken from
+// the context filled by [tracing.ScrapEngine] where possible.
+type translator struct {
+	info *types.Info
+	ctx  *tracing.Context
+
+	// result is the name of the error result of the function: either its name
+	// or syntheticErrName. It is empty if the function does not return errors.
+	result string
+}
+
+func (t *translator) stmts(list []ast.Stmt) []Node {
+	var res []Node
+	for _, stmt := range list {
+		res = append(res, t.stmt(stmt)...)
+	}
+
+	return res
+}
+
+func (t *translator) stmt(stmt ast.Stmt) []Node {
+	switch s := stmt.(type) {
+	case *ast.AssignStmt:
+		return t.assign(s.Lhs, s.Rhs)
+
+	case *ast.DeclStmt:
+		gen, ok := s.Decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			return nil
+		}
+
+		var res []Node
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			lhs := make([]ast.Expr, len(vs.Names))
+			for i, name := range vs.Names {
+				lhs[i] = name
+			}
+			res = append(res, t.assign(lhs, vs.Values)...)
+		}
+		return res
+
+	case *ast.ExprStmt:
+		if node := t.exprStmt(s.X); node != nil {
+			return []Node{node}
+		}
+
+	case *ast.ReturnStmt:
+		return t.ret(s)
+
+	case *ast.BlockStmt:
+		return t.stmts(s.List)
+
+	case *ast.LabeledStmt:
+		return t.stmt(s.Stmt)
+
+	case *ast.IfStmt:
+		var res []Node
+		if s.Init != nil {
+			res = append(res, t.stmt(s.Init)...)
+		}
+		if node := t.ifStmt(s); node != nil {
+			res = append(res, node)
+		}
+		return res
+
+	case *ast.ForStmt:
+		var res []Node
+		if s.Init != nil {
+			res = append(res, t.stmt(s.Init)...)
+		}
+		return append(res, t.stmts(s.Body.List)...)
+
+	case *ast.RangeStmt:
+		return t.stmts(s.Body.List)
+
+	case *ast.SwitchStmt:
+		var res []Node
+		if s.Init != nil {
+			res = append(res, t.stmt(s.Init)...)
+		}
+		var tag string
+		if s.Tag != nil {
+			tag = types.ExprString(s.Tag)
+		}
+		if node := t.switchStmt(s, tag, s.Body); node != nil {
+			res = append(res, node)
+		}
+		return res
+
+	case *ast.TypeSwitchStmt:
+		var res []Node
+		if s.Init != nil {
+			res = append(res, t.stmt(s.Init)...)
+		}
+		if node := t.switchStmt(s, typeSwitchTag(s.Assign), s.Body); node != nil {
+			res = append(res, node)
+		}
+		return res
+
+	case *ast.SelectStmt:
+		return t.stmts(s.Body.List)
+
+	case *ast.CommClause:
+		return t.stmts(s.Body)
+	}
+
+	return nil
+}
+
+// ifStmt translates conditional statements. Returns nil if there is no error handling
+// in either branch.
+func (t *translator) ifStmt(stmt *ast.IfStmt) Node {
+	res := spanned(&cir.If{
+		Cond: types.ExprString(stmt.Cond),
+		Body: t.stmts(stmt.Body.List),
+	}, stmt.Pos(), stmt.End())
+	if stmt.Else != nil {
+		res.Else = t.stmt(stmt.Else)
+	}
+	if len(res.Body) == 0 && len(res.Else) == 0 {
+		return nil
+	}
+
+	return res
+}
+
+// switchStmt translates both switch and type switch statements. Returns nil if there
+// is no error handling in any of the clauses.
+func (t *translator) switchStmt(stmt ast.Stmt, tag string, body *ast.BlockStmt) Node {
+	res := spanned(&cir.Switch{Tag: tag}, stmt.Pos(), stmt.End())
+	var handles bool
+	for _, stmt := range body.List {
+		clause := stmt.(*ast.CaseClause)
+		c := cir.SwitchCase{
+			Body: t.stmts(clause.Body),
+		}
+		for _, expr := range clause.List {
+			c.List = append(c.List, types.ExprString(expr))
+		}
+
+		handles = handles || len(c.Body) > 0
+		res.Cases = append(res.Cases, c)
+	}
+	if !handles {
+		return nil
+	}
+
+	return res
+}
+
+// assign translates assignments having errors at the last position of a multi-value call
+// or anywhere in a list of single values:
+//
+//	data, err := os.ReadFile(path)
+//	err, other := f(), g()
+//	pe, ok := err.(*fs.PathError)
+func (t *translator) assign(lhs, rhs []ast.Expr) []Node {
+	if len(rhs) == 1 && len(lhs) > 1 {
+		switch v := ast.Unparen(rhs[0]).(type) {
+		case *ast.CallExpr:
+			tuple, ok := t.info.TypeOf(v).(*types.Tuple)
+			if !ok || tuple.Len() == 0 || !tracing.IsError(tuple.At(tuple.Len()-1).Type()) {
+				return nil
+			}
+			if node := t.assignNode(lhs[len(lhs)-1], v); node != nil {
+				return []Node{node}
+			}
+
+		case *ast.TypeAssertExpr:
+			if node := t.assert(errorDst(lhs[0]), lhs[0].Pos(), lhs[1], v); node != nil {
+				return []Node{node}
+			}
+		}
+
+		return nil
+	}
+
+	if len(lhs) != len(rhs) {
+		return nil
+	}
+
+	var res []Node
+	for i := range rhs {
+		if v, ok := ast.Unparen(rhs[i]).(*ast.TypeAssertExpr); ok {
+			if node := t.assert(errorDst(lhs[i]), lhs[i].Pos(), nil, v); node != nil {
+				res = append(res, node)
+			}
+			continue
+		}
+
+		if !tracing.IsError(t.info.TypeOf(rhs[i])) && !t.errorValue(lhs[i], rhs[i]) {
+			continue
+		}
+		if node := t.assignNode(lhs[i], rhs[i]); node != nil {
+			res = append(res, node)
+		}
+	}
+
+	return res
+}
+
+func (t *translator) assignNode(lhs, rhs ast.Expr) Node {
+	dst := errorDst(lhs)
+	if dst == nil {
+		return nil
+	}
+
+	return t.assignTo(dst, lhs.Pos(), rhs)
+}
+
+// errorValue checks if the error variable is given nil or a value of a concrete type
+// implementing error.
+//
+//	var err error = &fs.PathError{…}
+func (t *translator) errorValue(lhs, rhs ast.Expr) bool {
+	if !tracing.IsError(t.info.TypeOf(lhs)) {
+		return false
+	}
+
+	return tracing.IsNilExpr(t.info, rhs) || tracing.ImplementsError(t.info.TypeOf(rhs))
+}
+
+// assignTo translates an assignment to the given variable. The assignment source
+// starts at the start position.
+func (t *translator) assignTo(dst cir.ErrorVarNode, start token.Pos, rhs ast.Expr) Node {
+	src := t.source(rhs)
+	if src == nil {
+		return nil
+	}
+
+	return spanned(&cir.Assign{Dst: dst, Src: src}, start, rhs.End())
+}
+
+// assert translates type assertions producing errors:
+//
+//	err := v.(error)
+//	pe, ok := err.(*fs.PathError)
+func (t *translator) assert(dst cir.ErrorVarNode, start token.Pos, guard ast.Expr, expr *ast.TypeAssertExpr) Node {
+	if expr.Type == nil || !tracing.ImplementsError(t.info.TypeOf(expr.Type)) {
+		return nil
+	}
+
+	src := varOf(expr.X)
+	if dst == nil || src == nil {
+		return nil
+	}
+
+	res := &cir.AssignAssert{
+		Dst:  dst,
+		Src:  src,
+		Type: typeRef(t.info.TypeOf(expr.Type)),
+	}
+	if guard != nil {
+		if id, ok := guard.(*ast.Ident); ok {
+			res.Guard = id.Name
+		}
+	}
+
+	return spanned(res, start, expr.End())
+}
+
+// source translates an expression producing an error. Returns nil if it is not supported.
+func (t *translator) source(expr ast.Expr) cir.Expr {
+	switch v := ast.Unparen(expr).(type) {
+	case *ast.CallExpr:
+		switch node := t.ctx.GetByPos(v.Lparen).(type) {
+		case *cir.ExprNew:
+			return node
+		case *cir.ExprWrap:
+			return node
+		case *cir.ExprJoin:
+			return node
+		case *cir.ExprAlias:
+			return node
+		case *cir.ExprCall:
+			return node
+		case *cir.ExprType:
+			// Conversions to types implementing error.
+			return node
+		}
+
+		// Calls of unknown functions, calls with errors documented to be nil, etc.
+		return spanned(&cir.ExprCall{
+			HasArgs: len(v.Args) > 0,
+			Ref:     funcRef(t.info, v),
+		}, v.Pos(), v.End())
+
+	case *ast.Ident:
+		if tracing.IsNilExpr(t.info, v) {
+			return spanned(&cir.ExprNil{}, v.Pos(), v.End())
+		}
+
+		obj, ok := t.info.Uses[v].(*types.Var)
+		if !ok {
+			return nil
+		}
+		if tracing.IsPackageLevel(obj) {
+			return spanned(&cir.ExprSentinel{Ref: objRef(obj)}, v.Pos(), v.End())
+		}
+		return spanned(&cir.ExprAlias{Target: v.Name}, v.Pos(), v.End())
+
+	case *ast.SelectorExpr:
+		obj, ok := t.info.Uses[v.Sel].(*types.Var)
+		if !ok || !tracing.IsPackageLevel(obj) {
+			return nil
+		}
+		return spanned(&cir.ExprSentinel{Ref: objRef(obj)}, v.Pos(), v.End())
+
+	case *ast.CompositeLit:
+		if node, ok := t.ctx.GetByPos(v.Lbrace).(*cir.ExprType); ok {
+			return node
+		}
+
+	case *ast.UnaryExpr:
+		if node, ok := t.ctx.GetByPos(v.Pos()).(*cir.ExprType); ok {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// exprStmt translates logging calls of errors. Panics are logs of the fatal level.
+func (t *translator) exprStmt(expr ast.Expr) Node {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+
+	if id, ok := ast.Unparen(call.Fun).(*ast.Ident); ok && len(call.Args) == 1 {
+		if b, ok := t.info.Uses[id].(*types.Builtin); ok && b.Name() == "panic" {
+			v := varOf(call.Args[0])
+			if v == nil || !tracing.IsError(t.info.TypeOf(call.Args[0])) {
+				return nil
+			}
+
+			return spanned(&cir.Log{
+				Var:   v,
+				Level: cir.LogLevelFatal,
+				Ref:   cir.Reference{Package: builtinPackage, Name: "panic"},
+			}, call.Pos(), call.End())
+		}
+	}
+
+	log, ok := t.ctx.GetByPos(call.Lparen).(*cir.Log)
+	if !ok || log.Var == nil {
+		return nil
+	}
+
+	return log
+}
+
+// ret translates returns of errors. Error sources of direct returns are assigned
+// to the error result before the return:
+//
+//	return errors.New("error")
+//
+// Success returns are not translated.
+func (t *translator) ret(stmt *ast.ReturnStmt) []Node {
+	if t.result == "" {
+		return nil
+	}
+	if len(stmt.Results) == 0 {
+		return []Node{spanned(&cir.Return{
+			Var:  t.resultVar(stmt),
+			Bare: true,
+		}, stmt.Pos(), stmt.End())}
+	}
+
+	last := stmt.Results[len(stmt.Results)-1]
+	if tracing.IsNilExpr(t.info, last) {
+		return nil
+	}
+	if v, ok := ast.Unparen(last).(*ast.Ident); ok && !tracing.IsSentinelExpr(t.info, last) {
+		return []Node{spanned(&cir.Return{Var: varOf(v)}, stmt.Pos(), stmt.End())}
+	}
+
+	var res []Node
+	if v, ok := ast.Unparen(last).(*ast.TypeAssertExpr); ok {
+		if node := t.assert(t.resultVar(last), last.Pos(), nil, v); node != nil {
+			res = append(res, node)
+		}
+	} else if node := t.assignTo(t.resultVar(last), last.Pos(), last); node != nil {
+		res = append(res, node)
+	}
+
+	return append(res, spanned(&cir.Return{Var: t.resultVar(last)}, stmt.Pos(), stmt.End()))
+}
+
+// resultVar returns the error result variable located at the given node.
+func (t *translator) resultVar(at ast.Node) *cir.ExprVar {
+	return spanned(&cir.ExprVar{Name: t.result}, at.Pos(), at.End())
+}
+
+func typeSwitchTag(stmt ast.Stmt) string {
+	var lhs string
+	expr := stmt
+	if v, ok := stmt.(*ast.AssignStmt); ok && len(v.Lhs) == 1 && len(v.Rhs) == 1 {
+		lhs = types.ExprString(v.Lhs[0]) + " := "
+		expr = &ast.ExprStmt{X: v.Rhs[0]}
+	}
+
+	es, ok := expr.(*ast.ExprStmt)
+	if !ok {
+		return ""
+	}
+	assert, ok := ast.Unparen(es.X).(*ast.TypeAssertExpr)
+	if !ok {
+		return ""
+	}
+
+	return lhs + types.ExprString(assert.X) + ".(type)"
+}
+
+// resultName returns the name error results of the function are referred with.
+func resultName(info *types.Info, fn *ast.FuncDecl) string {
+	results := fn.Type.Results
+	if results == nil || len(results.List) == 0 {
+		return ""
+	}
+
+	last := results.List[len(results.List)-1]
+	if !tracing.IsError(info.TypeOf(last.Type)) {
+		return ""
+	}
+	if len(last.Names) > 0 && last.Names[len(last.Names)-1].Name != "_" {
+		return last.Names[len(last.Names)-1].Name
+	}
+
+	return syntheticErrName
+}
+
+// errorParams returns names of error parameters of the function.
+func errorParams(info *types.Info, fn *ast.FuncDecl) []string {
+	var res []string
+	for _, field := range fn.Type.Params.List {
+		if !tracing.IsError(info.TypeOf(field.Type)) {
+			continue
+		}
+		for _, name := range field.Names {
+			if name.Name != "_" {
+				res = append(res, name.Name)
+			}
+		}
+	}
+
+	return res
+}
+
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+
+	typ := fn.Recv.List[0].Type
+	for {
+		switch v := typ.(type) {
+		case *ast.StarExpr:
+			typ = v.X
+			continue
+		case *ast.IndexExpr:
+			typ = v.X
+			continue
+		case *ast.IndexListExpr:
+			typ = v.X
+			continue
+		case *ast.Ident:
+			return v.Name + "." + fn.Name.Name
+		}
+
+		return fn.Name.Name
+	}
+}
+
+// stdImporter imports standard library packages from sources.
+var stdImporter = &lockedImporter{
+	imp: importer.ForCompiler(token.NewFileSet(), "source", nil),
+}
+
+// lockedImporter makes an importer safe for concurrent use.
+type lockedImporter struct {
+	mu  sync.Mutex
+	imp types.Importer
+}
+
+func (i *lockedImporter) Import(path string) (*types.Package, error) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.imp.Import(path)
+}
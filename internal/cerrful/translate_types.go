@@ -0,0 +1,63 @@
+package cerrful
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/sirkon/cerrful/internal/cir"
+	"github.com/sirkon/cerrful/internal/tracing"
+)
+
+// builtinPackage is the package name references to builtins are made with.
+const builtinPackage = "builtin"
+
+func funcRef(info *types.Info, call *ast.CallExpr) cir.Reference {
+	return tracing.FuncRef(info, call)
+}
+
+// errorDst returns a CIR variable an error is assigned to.
+func errorDst(expr ast.Expr) cir.ErrorVarNode {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+	if id.Name == "_" {
+		return spanned(&cir.ExprVarHidden{}, id.Pos(), id.End())
+	}
+
+	return spanned(&cir.ExprVar{Name: id.Name}, id.Pos(), id.End())
+}
+
+func varOf(expr ast.Expr) *cir.ExprVar {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok || id.Name == "_" {
+		return nil
+	}
+
+	return spanned(&cir.ExprVar{Name: id.Name}, id.Pos(), id.End())
+}
+
+// spanned sets the source range of the node and returns it.
+func spanned[N cir.Node](node N, start, end token.Pos) N {
+	node.SetSpan(start, end)
+	return node
+}
+
+// typeRef returns a reference to the type, pointers are dereferenced. The error interface
+// is referred as a builtin.
+func typeRef(t types.Type) cir.Reference {
+	if tracing.IsError(t) {
+		return cir.Reference{Package: builtinPackage, Name: "error"}
+	}
+
+	ref, _ := tracing.TypeRef(t)
+	return ref
+}
+
+func objRef(obj types.Object) cir.Reference {
+	return cir.Reference{
+		Package: obj.Pkg().Path(),
+		Name:    obj.Name(),
+	}
+}
@@ -27,10 +27,24 @@ const (
 	CER070ReturnInDefinedErrorState
 	CER080NoErrorDelegation
 	CER090ErrorMustBeLastReturnValue // ← добавлено
+
+	// Deprecated: split into CER110–CER115 and CER120, kept so that codes following it are not
+	// renumbered.
 	CER100TextAndStyleRules
+
 	CER0101AnnotationFormatMustBeLiteral
 	CER102AnnotationFormatMustEndWithW
 	CER150NoLogAndReturn
+	CER075NoRedundantErrorCheck
+	CER103NoOpaqueErrorFormat
+	CER104AnnotationMessageMustBeUnique
+	CER110MessageMustStartLowercase
+	CER111NoTrailingPunctuation
+	CER112NoNoiseWords
+	CER113NoFormatVerbsWithoutArgs
+	CER114NoSurroundingWhitespace
+	CER115MessageMaxLength
+	CER120NoForbiddenTerms
 )
 
 // String returns the canonical code and short name of the rule.
@@ -60,13 +74,33 @@ func (r Rule) String() string {
 	case CER090ErrorMustBeLastReturnValue:
 		return "CER090: ErrorMustBeLastReturnValue"
 	case CER100TextAndStyleRules:
-		return "CER100–CER145: TextAndStyleRules"
+		return "CER100: TextAndStyleRules"
 	case CER0101AnnotationFormatMustBeLiteral:
 		return "CER0101: AnnotationFormatMustBeLiteral"
 	case CER102AnnotationFormatMustEndWithW:
 		return "CER102: AnnotationFormatMustEndWithW"
+	case CER103NoOpaqueErrorFormat:
+		return "CER103: NoOpaqueErrorFormat"
+	case CER104AnnotationMessageMustBeUnique:
+		return "CER104: AnnotationMessageMustBeUnique"
+	case CER110MessageMustStartLowercase:
+		return "CER110: MessageMustStartLowercase"
+	case CER111NoTrailingPunctuation:
+		return "CER111: NoTrailingPunctuation"
+	case CER112NoNoiseWords:
+		return "CER112: NoNoiseWords"
+	case CER113NoFormatVerbsWithoutArgs:
+		return "CER113: NoFormatVerbsWithoutArgs"
+	case CER114NoSurroundingWhitespace:
+		return "CER114: NoSurroundingWhitespace"
+	case CER115MessageMaxLength:
+		return "CER115: MessageMaxLength"
+	case CER120NoForbiddenTerms:
+		return "CER120: NoForbiddenTerms"
 	case CER150NoLogAndReturn:
 		return "CER150: NoLogAndReturn"
+	case CER075NoRedundantErrorCheck:
+		return "CER075: NoRedundantErrorCheck"
 	default:
 		return fmt.Sprintf("rule-unknown(%d)", r)
 	}
@@ -98,13 +132,33 @@ func (r Rule) Description() string {
 	case CER090ErrorMustBeLastReturnValue:
 		return "Returning functions must place the error result as the last return value."
 	case CER100TextAndStyleRules:
-		return "Message formatting and forbidden terms."
+		return "Deprecated, message text and style are checked by CER110–CER115 and forbidden terms by CER120."
 	case CER0101AnnotationFormatMustBeLiteral:
 		return "Annotation format must be a string literal."
 	case CER102AnnotationFormatMustEndWithW:
-		return "Annotation format must end with ': %w' fragment."
+		return "Annotation format must end with ': %w' fragment, every other %w of multi-wrap formats must follow ': ' as well."
+	case CER103NoOpaqueErrorFormat:
+		return "Errors must be annotated with %w: %v, %s, %q and Error() keep the text only, so errors.Is and errors.As cannot see the error through the annotation."
+	case CER104AnnotationMessageMustBeUnique:
+		return "Annotation messages must be unique within the uniqueness scope, otherwise error texts do not tell which call failed."
+	case CER110MessageMustStartLowercase:
+		return "Error messages must start with a lowercase letter unless they start with an acronym, they are usually printed after other text."
+	case CER111NoTrailingPunctuation:
+		return "Error messages must not end with punctuation or a newline, they are usually followed by other text."
+	case CER112NoNoiseWords:
+		return "Error messages must not say \"failed to\", \"unable to\" or \"could not\" and must neither start nor end with \"error\", an error is a failure already."
+	case CER113NoFormatVerbsWithoutArgs:
+		return "Error messages of constructors must not contain format verbs when there are no arguments to format."
+	case CER114NoSurroundingWhitespace:
+		return "Error messages must not have leading or trailing whitespace."
+	case CER115MessageMaxLength:
+		return "Error messages must be short, details belong to annotations of callers."
+	case CER120NoForbiddenTerms:
+		return "Error messages must not contain configured forbidden terms."
 	case CER150NoLogAndReturn:
 		return "Error must be either logged or returned, never both."
+	case CER075NoRedundantErrorCheck:
+		return "Error state checks must neither repeat nor contradict what is already known."
 	default:
 		return fmt.Sprintf("unknwon-rule(%d)", r)
 	}
@@ -130,4 +184,14 @@ func ErrorMustBeLastReturnValue() Rule    { return CER090ErrorMustBeLastReturnVa
 func TextAndStyleRules() Rule             { return CER100TextAndStyleRules }
 func AnnotationFormatMustBeLiteral() Rule { return CER0101AnnotationFormatMustBeLiteral }
 func AnnotationFormatMustEndWithW() Rule  { return CER102AnnotationFormatMustEndWithW }
+func NoOpaqueErrorFormat() Rule           { return CER103NoOpaqueErrorFormat }
+func AnnotationMessageMustBeUnique() Rule { return CER104AnnotationMessageMustBeUnique }
+func MessageMustStartLowercase() Rule     { return CER110MessageMustStartLowercase }
+func NoTrailingPunctuation() Rule         { return CER111NoTrailingPunctuation }
+func NoNoiseWords() Rule                  { return CER112NoNoiseWords }
+func NoFormatVerbsWithoutArgs() Rule      { return CER113NoFormatVerbsWithoutArgs }
+func NoSurroundingWhitespace() Rule       { return CER114NoSurroundingWhitespace }
+func MessageMaxLength() Rule              { return CER115MessageMaxLength }
+func NoForbiddenTerms() Rule              { return CER120NoForbiddenTerms }
 func NoLogAndReturn() Rule                { return CER150NoLogAndReturn }
+func NoRedundantErrorCheck() Rule         { return CER075NoRedundantErrorCheck }
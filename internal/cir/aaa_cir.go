@@ -1,10 +1,21 @@
 package cir
 
+import "go/token"
+
 // Node is the base interface implemented by all CIR node types.
 // Each node denotes a single error-handling construct identified in Go source code
 // (e.g., assignment, creation, wrapping, check, propagation).
 type Node interface {
 	isNode()
+
+	// Pos returns the position of the first character of the node source.
+	Pos() token.Pos
+
+	// End returns the position of the character immediately after the node source.
+	End() token.Pos
+
+	// SetSpan sets the source range of the node.
+	SetSpan(start, end token.Pos)
 }
 
 // Statement marks nodes that represent Go statements
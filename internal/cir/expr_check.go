@@ -2,9 +2,13 @@ package cir
 
 // ErrorTypeIsCheck represents `errors.Is` (and equivalents) usage in a source code. The type is being dug
 // is stored in Type, the [errors.Is] thing used referenced in Ref. The source of an error is in Src.
+// Cases of type switches over errors are represented this way too, with an empty Ref.
 //
 //	errors.Is(err, io.EOF) // Var: "err", Type: "io"."EOF", Ref: "errors"."IO"
+//	case *fs.PathError:    // Var: "err", Type: "io/fs"."PathError"
 type ErrorTypeIsCheck struct {
+	Span
+
 	Src  *ExprVar
 	Type Reference
 
@@ -16,6 +20,8 @@ type ErrorTypeIsCheck struct {
 //
 //	os.IsExists(err) // Var: "err", Type: "os"."IsExists".
 type ErrorTypeIsHelperCheck struct {
+	Span
+
 	Src *ExprVar
 	Ref Reference
 }
@@ -24,6 +30,8 @@ type ErrorTypeIsHelperCheck struct {
 //
 //	err != nil // Var: "err"
 type ErrorValueIsNotNil struct {
+	Span
+
 	Src *ExprVar
 }
 
@@ -31,6 +39,8 @@ type ErrorValueIsNotNil struct {
 //
 //	err == nil // Var: "err"
 type ErrorValueIsNil struct {
+	Span
+
 	Src *ExprVar
 }
 
@@ -39,6 +49,8 @@ type ErrorValueIsNil struct {
 //
 //	err == io.EOF // Var: "err", RHS: "io"."EOF"
 type ErrorValueEQ struct {
+	Span
+
 	Src *ExprVar
 	RHS Expr
 }
@@ -48,6 +60,8 @@ type ErrorValueEQ struct {
 //
 //	err != io.EOF // Var: "err", Type: "io.EOF"
 type ErrorValueNEQ struct {
+	Span
+
 	Src *ExprVar
 	RHS Expr
 }
@@ -63,6 +77,8 @@ type ErrorValueNEQ struct {
 //	errors.As(err, io.EOF)
 //	errors.As(err, os.Rename(…))
 type ErrorTypeExtract struct {
+	Span
+
 	Src    *ExprVar
 	Target *ExprVar
 	Ref    Reference
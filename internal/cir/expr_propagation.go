@@ -4,7 +4,9 @@ package cir
 //
 //	err := nil
 //	err = nil
-type ExprNil struct{}
+type ExprNil struct {
+	Span
+}
 
 // ExprAlias represents error aliasing — a direct assignment from
 // another error variable.
@@ -15,6 +17,8 @@ type ExprNil struct{}
 // function are classified as aliases. Assignments from an outer
 // scope are considered ExprSentinel.
 type ExprAlias struct {
+	Span
+
 	Target string
 }
 
@@ -22,14 +26,21 @@ type ExprAlias struct {
 //
 //	err := io.EOF // Ref: "io"."EOF"
 type ExprSentinel struct {
+	Span
+
 	Ref Reference
 }
 
 // ExprType represents an error value constructed as a type
-// implementing the [error] interface.
+// implementing the [error] interface: a composite literal, its address
+// or a conversion.
 //
-//	err := myerrs.MyError{…} // Ref: "path/to/myerrs"."MyError"
+//	err := myerrs.MyError{…}  // Ref: "path/to/myerrs"."MyError"
+//	err := &myerrs.MyError{…} // Ref: "path/to/myerrs"."MyError"
+//	err := myerrs.Code(404)   // Ref: "path/to/myerrs"."Code"
 type ExprType struct {
+	Span
+
 	Ref Reference
 }
 
@@ -38,6 +49,8 @@ type ExprType struct {
 //
 //	json.Unmarshal(data) // HasArgs: true, Ref: "json"."Unmarshal"
 type ExprCall struct {
+	Span
+
 	HasArgs bool
 	Ref     Reference
 }
@@ -47,17 +60,44 @@ type ExprCall struct {
 //
 //	errors.Wrap(err, "do something")    // Var: <ExprFor>(err), Msg: "do something", Ref: "custom/errs/pkg"."Wrap"
 //	fmt.Errorf("do something: %w", err) // Var: <ExprFor>(err), Msg: "do something", Ref: "fmt"."Errorf"
+//	fmt.Errorf("do something: %v", err) // … Opaque: true
+//
+// Opaque is set if the error is formatted with %v, %s, %q or its Error method rather
+// than %w: only its text is kept, so errors.Is and errors.As cannot see it through the wrap.
 type ExprWrap struct {
-	Var *ExprVar
-	Msg string
-	Ref Reference
+	Span
+
+	Var    *ExprVar
+	Msg    string
+	Ref    Reference
+	Opaque bool
 }
 
-// ExprNew represents creation of a new error instance.
+// ExprJoin represents an error combining several errors at once: joined ones or those
+// wrapped with multiple %w verbs. Vars of errors that are not variables have empty names.
+// Msg is the format with wrap verbs and their ": " prefixes cut out, it is empty for joins.
+// Opaque has the same meaning as for [ExprWrap].
 //
-//	errors.New("error")  // Ref: "errors"."New"
-//	fmt.Errorf("errorf") // Ref: "fmt"."Errorf"
+//	errors.Join(err1, err2)                       // Vars: [err1, err2], Ref: "errors"."Join"
+//	fmt.Errorf("read: %w, close: %w", err1, err2) // Vars: [err1, err2], Msg: "read, close", Ref: "fmt"."Errorf"
+type ExprJoin struct {
+	Span
+
+	Vars   []*ExprVar
+	Msg    string
+	Ref    Reference
+	Opaque bool
+}
+
+// ExprNew represents creation of a new error instance. Msg is empty if the message
+// is not a literal.
+//
+//	errors.New("error")  // Msg: "error", Ref: "errors"."New"
+//	fmt.Errorf("errorf") // Msg: "errorf", Ref: "fmt"."Errorf"
 type ExprNew struct {
+	Span
+
+	Msg string
 	Ref Reference
 }
 
@@ -73,5 +113,7 @@ func (*ExprCall) isNode()     {}
 func (*ExprCall) isExpr()     {}
 func (*ExprWrap) isNode()     {}
 func (*ExprWrap) isExpr()     {}
+func (*ExprJoin) isNode()     {}
+func (*ExprJoin) isExpr()     {}
 func (*ExprNew) isNode()      {}
 func (*ExprNew) isExpr()      {}
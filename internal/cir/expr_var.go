@@ -3,6 +3,8 @@ package cir
 // ExprVar represents an explicit error variable reference,
 // such as `err` in either a check (`if err != nil`) or an assignment (`err := call()`).
 type ExprVar struct {
+	Span
+
 	// Name is the identifier of the error variable in the current scope.
 	Name string
 }
@@ -11,7 +13,9 @@ type ExprVar struct {
 // such as `_` in an assignment like `data, _ := os.ReadFile(path)`.
 //
 // This node is used to track places where an error result is explicitly discarded.
-type ExprVarHidden struct{}
+type ExprVarHidden struct {
+	Span
+}
 
 // Interface markers.
 func (*ExprVar) isNode()               {}
@@ -0,0 +1,18 @@
+package cir
+
+// Function represents CIR of a single Go function.
+type Function struct {
+	// Name is the name of the function. Methods are named after their receiver
+	// types, like "Type.Method".
+	Name string
+
+	// Result is the name the error result of the function is referred with: either
+	// its name or the synthetic "@err" one. It is empty if the last result of the
+	// function is not an error.
+	Result string
+
+	// Params are names of error parameters of the function.
+	Params []string
+
+	Nodes []Node
+}
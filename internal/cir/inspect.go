@@ -0,0 +1,79 @@
+package cir
+
+// Inspect traverses the node and its children in depth-first order. It calls f(node)
+// for every node met. If f returns true, Inspect invokes f recursively for each of
+// the non-nil children of the node.
+func Inspect(node Node, f func(Node) bool) {
+	if node == nil || !f(node) {
+		return
+	}
+
+	for _, child := range children(node) {
+		Inspect(child, f)
+	}
+}
+
+func children(node Node) []Node {
+	var res []Node
+	add := func(nodes ...any) {
+		for _, n := range nodes {
+			if n, ok := n.(Node); ok && !isNilNode(n) {
+				res = append(res, n)
+			}
+		}
+	}
+
+	switch v := node.(type) {
+	case *Assign:
+		add(v.Dst, v.Src)
+	case *AssignCheckFlag:
+		add(v.Src)
+	case *AssignAssert:
+		add(v.Dst, v.Src)
+	case *Log:
+		add(v.Var)
+	case *Return:
+		add(v.Var)
+	case *If:
+		res = append(res, v.Body...)
+		res = append(res, v.Else...)
+	case *Switch:
+		for _, c := range v.Cases {
+			res = append(res, c.Body...)
+		}
+	case *ExprWrap:
+		add(v.Var)
+	case *ExprJoin:
+		for _, x := range v.Vars {
+			add(x)
+		}
+	case *ErrorTypeIsCheck:
+		add(v.Src)
+	case *ErrorTypeIsHelperCheck:
+		add(v.Src)
+	case *ErrorValueIsNotNil:
+		add(v.Src)
+	case *ErrorValueIsNil:
+		add(v.Src)
+	case *ErrorValueEQ:
+		add(v.Src, v.RHS)
+	case *ErrorValueNEQ:
+		add(v.Src, v.RHS)
+	case *ErrorTypeExtract:
+		add(v.Src, v.Target)
+	}
+
+	return res
+}
+
+// isNilNode checks if the node is a typed nil, like a nil *ExprVar stored in Node.
+func isNilNode(node Node) bool {
+	switch v := node.(type) {
+	case *ExprVar:
+		return v == nil
+	case *ExprVarHidden:
+		return v == nil
+	default:
+		return false
+	}
+}
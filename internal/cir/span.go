@@ -0,0 +1,26 @@
+package cir
+
+import "go/token"
+
+// Span is the source range of a node. It is embedded into every node type, so nodes
+// can point at the code they were made of.
+type Span struct {
+	StartPos token.Pos
+	EndPos   token.Pos
+}
+
+// Pos returns the position of the first character of the node source.
+func (s *Span) Pos() token.Pos {
+	return s.StartPos
+}
+
+// End returns the position of the character immediately after the node source.
+func (s *Span) End() token.Pos {
+	return s.EndPos
+}
+
+// SetSpan sets the source range of the node.
+func (s *Span) SetSpan(start, end token.Pos) {
+	s.StartPos = start
+	s.EndPos = end
+}
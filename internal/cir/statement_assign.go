@@ -6,13 +6,16 @@ package cir
 //	data, err := os.ReadFile(…)         // Dst: &ExprVar{Name: "err"}, Var: &ExprCall{…}
 //	err := os.Rename(oldname, newname)
 //	_, err := writer.Write(…)
+//	data, _ := os.ReadFile(…)           // Dst: &ExprVarHidden{}
 //
 // And these will not be represented as such:
 //
 //	myErr, ok := err.(*MyError) // Because the error is not the last value.
 //	xp2 := math.Pow(x, 2)       // No errors at all in return values.
 type Assign struct {
-	Dst ExprVar
+	Span
+
+	Dst ErrorVarNode
 	Src Expr
 }
 
@@ -25,6 +28,8 @@ type Assign struct {
 //
 // and so on.
 type AssignCheckFlag struct {
+	Span
+
 	Dst string
 	Src ErrorTypeGuess
 }
@@ -35,6 +40,8 @@ type AssignCheckFlag struct {
 //   - v, ok := expr.(someErrorType) // Dst: "v", Guard: "ok", Src: "expr", Type: "someErrorType"
 //   - v := expr.(someErrorType)     // … Guard:"" …
 type AssignAssert struct {
+	Span
+
 	Dst   ErrorVarNode
 	Guard string
 	Src   Expr
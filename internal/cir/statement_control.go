@@ -0,0 +1,57 @@
+package cir
+
+// Return represents a return of an error from a function. Var is the returned
+// error variable: an explicit one, the named error result or the synthetic "@err"
+// one for direct returns of error expressions.
+//
+//	return err                         // Var: "err"
+//	return fmt.Errorf("do: %w", err)   // Var: "@err", preceded by the Assign of "@err"
+//	return                             // Var: <named result>, Bare: true
+//
+// Success returns, i.e. where the error result is nil, are not represented.
+type Return struct {
+	Span
+
+	Var  *ExprVar
+	Bare bool
+}
+
+// If represents a conditional statement having error handling in any of its branches.
+// Cond is the source text of the condition. Else holds nodes of the else branch,
+// "else if" is an If node there.
+//
+//	if err != nil { … } // Cond: "err != nil"
+type If struct {
+	Span
+
+	Cond string
+	Body []Node
+	Else []Node
+}
+
+// Switch represents a switch or a type switch statement having error handling in
+// any of its clauses. Tag is the source text of the switch tag, it is empty for
+// tagless switches.
+//
+//	switch err { … }            // Tag: "err"
+//	switch e := err.(type) { … } // Tag: "e := err.(type)"
+type Switch struct {
+	Span
+
+	Tag   string
+	Cases []SwitchCase
+}
+
+// SwitchCase represents a clause of a Switch. List holds source texts of clause
+// expressions or types, it is empty for the default clause.
+type SwitchCase struct {
+	List []string
+	Body []Node
+}
+
+func (*Return) isNode()      {}
+func (*Return) isStatement() {}
+func (*If) isNode()          {}
+func (*If) isStatement()     {}
+func (*Switch) isNode()      {}
+func (*Switch) isStatement() {}
@@ -21,6 +21,8 @@ import "fmt"
 //	panic(err)
 //	// Var: "err", Level: "fatal", Msg: "", Ref: "builtin"."panic"
 type Log struct {
+	Span
+
 	Var   Expr
 	Level LogLevel
 	Msg   string
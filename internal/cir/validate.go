@@ -0,0 +1,245 @@
+package cir
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Violation describes a node breaking CIR invariants.
+type Violation struct {
+	// Function is the name of the function the node belongs to.
+	Function string
+
+	// Node is the offending node. It is nil for missing nodes.
+	Node Node
+
+	Message string
+}
+
+func (v Violation) String() string {
+	return v.Function + ": " + v.Message
+}
+
+// Validate checks functions against CIR invariants:
+//
+//   - Assignments have a destination and exactly one source of the supported kinds.
+//   - Wraps, joins and aliases refer to existing errors: parameters, the error result and
+//     variables assigned before.
+//   - Success returns are pruned: no return of a variable nil was assigned to right before.
+//   - Only functions whose last result is an error return errors.
+//
+// Returns nil if all invariants hold.
+func Validate(fns ...Function) []Violation {
+	var res []Violation
+	for i := range fns {
+		v := &validator{
+			fn:       &fns[i],
+			assigned: map[string]bool{},
+			declared: map[string]bool{},
+		}
+		for _, node := range v.fn.Nodes {
+			Inspect(node, func(n Node) bool {
+				if name := assignedName(n); name != "" {
+					v.declared[name] = true
+				}
+				return true
+			})
+		}
+
+		v.nodes(v.fn.Nodes)
+		res = append(res, v.res...)
+	}
+
+	return res
+}
+
+type validator struct {
+	fn *Function
+
+	// assigned holds variables assigned by nodes met so far.
+	assigned map[string]bool
+
+	// declared holds variables assigned anywhere in the function.
+	declared map[string]bool
+
+	res []Violation
+}
+
+func (v *validator) nodes(list []Node) {
+	for i, node := range list {
+		if ret, ok := node.(*Return); ok && ret != nil && ret.Var != nil && i > 0 {
+			if a, ok := list[i-1].(*Assign); ok && assignedName(a) == ret.Var.Name {
+				if _, ok := a.Src.(*ExprNil); ok {
+					v.add(ret, "success return of %s is not pruned", ret.Var.Name)
+				}
+			}
+		}
+
+		v.node(node)
+	}
+}
+
+func (v *validator) node(node Node) {
+	if isNilValue(node) {
+		v.add(nil, "nil node")
+		return
+	}
+
+	switch n := node.(type) {
+	case *Assign:
+		v.dst(n, n.Dst)
+		switch src := n.Src.(type) {
+		case nil:
+			v.add(n, "assignment has no source")
+		case *ExprNil, *ExprSentinel, *ExprType, *ExprCall, *ExprNew:
+			if isNilValue(src) {
+				v.add(n, "assignment source %T is nil", src)
+			}
+		case *ExprAlias:
+			if src == nil {
+				v.add(n, "assignment source %T is nil", src)
+				break
+			}
+			v.ref(n, src.Target, "alias")
+		case *ExprWrap:
+			if src == nil || src.Var == nil {
+				v.add(n, "wrap refers to no error")
+				break
+			}
+			v.ref(n, src.Var.Name, "wrap")
+		case *ExprJoin:
+			if src == nil || len(src.Vars) == 0 {
+				v.add(n, "join refers to no errors")
+				break
+			}
+			for _, x := range src.Vars {
+				if x == nil {
+					v.add(n, "join refers to no error")
+					continue
+				}
+				v.ref(n, x.Name, "join")
+			}
+		default:
+			v.add(n, "unsupported assignment source %T", src)
+		}
+		v.assign(n)
+
+	case *AssignAssert:
+		v.dst(n, n.Dst)
+		switch src := n.Src.(type) {
+		case *ExprVar:
+			if src == nil {
+				v.add(n, "type assertion has no source")
+				break
+			}
+			v.ref(n, src.Name, "type assertion")
+		case nil:
+			v.add(n, "type assertion has no source")
+		default:
+			v.add(n, "unsupported type assertion source %T", src)
+		}
+		v.assign(n)
+
+	case *AssignCheckFlag:
+		if n.Src == nil || isNilValue(n.Src) {
+			v.add(n, "check flag %s has no source", n.Dst)
+		}
+
+	case *Return:
+		switch {
+		case v.fn.Result == "":
+			v.add(n, "return of an error from a function whose last result is not an error")
+		case n.Var == nil:
+			v.add(n, "return of no error")
+		case n.Bare && n.Var.Name != v.fn.Result:
+			v.add(n, "bare return of %s instead of the result %s", n.Var.Name, v.fn.Result)
+		default:
+			v.ref(n, n.Var.Name, "return")
+		}
+
+	case *Log:
+		if x, ok := n.Var.(*ExprVar); ok && x != nil {
+			v.ref(n, x.Name, "log")
+		}
+
+	case *If:
+		v.nodes(n.Body)
+		v.nodes(n.Else)
+
+	case *Switch:
+		for _, c := range n.Cases {
+			v.nodes(c.Body)
+		}
+	}
+}
+
+func (v *validator) dst(node Node, dst ErrorVarNode) {
+	switch d := dst.(type) {
+	case *ExprVar:
+		if d == nil || d.Name == "" {
+			v.add(node, "assignment to an unnamed variable")
+		}
+	case *ExprVarHidden:
+		if d == nil {
+			v.add(node, "assignment has no destination")
+		}
+	default:
+		v.add(node, "assignment has no destination")
+	}
+}
+
+// ref checks the variable is an existing error. Empty names stand for expressions
+// that are not variables.
+func (v *validator) ref(node Node, name, what string) {
+	switch {
+	case name == "" || name == v.fn.Result:
+	case slices.Contains(v.fn.Params, name):
+	case v.assigned[name]:
+	case !v.declared[name]:
+		v.add(node, "%s refers to undeclared %s", what, name)
+	default:
+		v.add(node, "%s refers to %s before it is assigned", what, name)
+	}
+}
+
+func (v *validator) assign(node Node) {
+	if name := assignedName(node); name != "" {
+		v.assigned[name] = true
+	}
+}
+
+func (v *validator) add(node Node, format string, a ...any) {
+	v.res = append(v.res, Violation{
+		Function: v.fn.Name,
+		Node:     node,
+		Message:  fmt.Sprintf(format, a...),
+	})
+}
+
+// assignedName returns the name of the variable assigned by the node.
+func assignedName(node Node) string {
+	var dst ErrorVarNode
+	switch n := node.(type) {
+	case *Assign:
+		dst = n.Dst
+	case *AssignAssert:
+		dst = n.Dst
+	}
+
+	if v, ok := dst.(*ExprVar); ok && v != nil {
+		return v.Name
+	}
+
+	return ""
+}
+
+// isNilValue checks if v is nil or a typed nil pointer.
+func isNilValue(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Pointer && rv.IsNil()
+}
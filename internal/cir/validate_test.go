@@ -0,0 +1,126 @@
+package cir
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	errVar := func(name string) *ExprVar {
+		return &ExprVar{Name: name}
+	}
+	call := &ExprCall{Ref: Reference{Package: "os", Name: "Open"}}
+	wrap := func(name string) *ExprWrap {
+		return &ExprWrap{
+			Var: errVar(name),
+			Msg: "open",
+			Ref: Reference{Package: "fmt", Name: "Errorf"},
+		}
+	}
+
+	tests := []struct {
+		name string
+		fn   Function
+		want []string
+	}{
+		{
+			name: "valid",
+			fn: Function{
+				Name:   "f",
+				Result: "@err",
+				Params: []string{"cause"},
+				Nodes: []Node{
+					&Assign{Dst: errVar("err"), Src: call},
+					&If{
+						Cond: "err != nil",
+						Body: []Node{
+							&Assign{Dst: errVar("@err"), Src: wrap("err")},
+							&Return{Var: errVar("@err")},
+						},
+					},
+					&Assign{Dst: errVar("err"), Src: wrap("cause")},
+					&Return{Var: errVar("err")},
+				},
+			},
+		},
+		{
+			name: "sources",
+			fn: Function{
+				Name: "f",
+				Nodes: []Node{
+					&Assign{Dst: errVar("err")},
+					&Assign{Src: call},
+					&Assign{Dst: errVar("err"), Src: errVar("x")},
+					&Assign{Dst: errVar("err"), Src: &ExprWrap{Msg: "open"}},
+				},
+			},
+			want: []string{
+				"f: assignment has no source",
+				"f: assignment has no destination",
+				"f: unsupported assignment source *cir.ExprVar",
+				"f: wrap refers to no error",
+			},
+		},
+		{
+			name: "wrap before assignment",
+			fn: Function{
+				Name:   "f",
+				Result: "@err",
+				Nodes: []Node{
+					&Assign{Dst: errVar("@err"), Src: wrap("err")},
+					&Assign{Dst: errVar("err"), Src: call},
+					&Return{Var: errVar("@err")},
+				},
+			},
+			want: []string{"f: wrap refers to err before it is assigned"},
+		},
+		{
+			name: "undeclared",
+			fn: Function{
+				Name:   "f",
+				Result: "@err",
+				Nodes: []Node{
+					&Assign{Dst: errVar("@err"), Src: wrap("rangeErr")},
+					&Return{Var: errVar("@err")},
+				},
+			},
+			want: []string{"f: wrap refers to undeclared rangeErr"},
+		},
+		{
+			name: "success return",
+			fn: Function{
+				Name:   "f",
+				Result: "err",
+				Nodes: []Node{
+					&Assign{Dst: errVar("err"), Src: &ExprNil{}},
+					&Return{Var: errVar("err"), Bare: true},
+				},
+			},
+			want: []string{"f: success return of err is not pruned"},
+		},
+		{
+			name: "no error result",
+			fn: Function{
+				Name: "f",
+				Nodes: []Node{
+					&Assign{Dst: errVar("err"), Src: call},
+					&Return{Var: errVar("err")},
+				},
+			},
+			want: []string{"f: return of an error from a function whose last result is not an error"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range Validate(tt.fn) {
+				got = append(got, v.String())
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("unexpected violations\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"strings"
+)
+
+// Boundary describes semantic boundaries errors must be annotated on. Packages are split
+// into layers: explicit groups go first, other packages are split by the scope. Errors
+// of calls of another layer must be annotated before they are returned.
+//
+// Packages of other modules are layers of their own.
+type Boundary struct {
+	Scope BoundaryScope
+
+	// Depth is the number of path elements below the module root which name layers
+	// of the directory scope. It is 1 if not set.
+	Depth int
+
+	Groups []BoundaryGroup
+}
+
+// BoundaryGroup is an explicit layer of packages.
+type BoundaryGroup struct {
+	// Name of the layer, the first pattern is used if it is empty.
+	Name string
+
+	// Patterns of import paths of packages belonging to the layer. Paths are either
+	// full or relative to the module root, the "/..." suffix matches subpackages:
+	//
+	//	internal/storage/...
+	Patterns []string
+}
+
+// Layer returns the name of the layer the package belongs to. The module is the path
+// of the module being analyzed, only the package scope and groups of full import paths
+// work if it is unknown.
+func (b Boundary) Layer(module, path string) string {
+	for _, g := range b.Groups {
+		for _, p := range g.Patterns {
+			if !matchPattern(module, p, path) {
+				continue
+			}
+
+			if g.Name != "" {
+				return g.Name
+			}
+			return g.Patterns[0]
+		}
+	}
+
+	if module == "" || !withinPath(module, path) {
+		return path
+	}
+
+	switch b.Scope {
+	case BoundaryScopeModule:
+		return module
+	case BoundaryScopeDirectory:
+		depth := max(b.Depth, 1)
+		rel := strings.Split(strings.TrimPrefix(strings.TrimPrefix(path, module), "/"), "/")
+		if rel[0] == "" {
+			return module
+		}
+		return module + "/" + strings.Join(rel[:min(depth, len(rel))], "/")
+	default:
+		return path
+	}
+}
+
+// matchPattern checks if the import path matches the pattern, which is either a full path
+// or one relative to the module.
+func matchPattern(module, pattern, path string) bool {
+	match := func(pattern string) bool {
+		if prefix, ok := strings.CutSuffix(pattern, "/..."); ok {
+			return withinPath(prefix, path)
+		}
+		return pattern == path
+	}
+
+	if match(pattern) {
+		return true
+	}
+
+	return module != "" && match(module+"/"+pattern)
+}
+
+// withinPath checks if the path is the root one or is nested in it.
+func withinPath(root, path string) bool {
+	return path == root || strings.HasPrefix(path, root+"/")
+}
+
+// BoundaryScope defines how packages not belonging to explicit groups are split into layers.
+type BoundaryScope int
+
+const (
+	_ BoundaryScope = iota
+
+	// BoundaryScopePackage makes every package a layer.
+	BoundaryScopePackage
+
+	// BoundaryScopeModule makes the whole module a single layer.
+	BoundaryScopeModule
+
+	// BoundaryScopeDirectory splits the module into directory subtrees.
+	BoundaryScopeDirectory
+)
+
+func (s BoundaryScope) String() string {
+	v, err := s.MarshalText()
+	if err != nil {
+		return fmt.Sprintf("boundary-scope-invalid(%d)", s)
+	}
+
+	return string(v)
+}
+
+var _ encoding.TextUnmarshaler = (*BoundaryScope)(nil)
+
+func (s *BoundaryScope) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "package":
+		*s = BoundaryScopePackage
+		return nil
+	case "module":
+		*s = BoundaryScopeModule
+		return nil
+	case "directory":
+		*s = BoundaryScopeDirectory
+		return nil
+	default:
+		return fmt.Errorf("unknown boundary scope %q", b)
+	}
+}
+
+func (s BoundaryScope) MarshalText() ([]byte, error) {
+	switch s {
+	case BoundaryScopePackage:
+		return []byte("package"), nil
+	case BoundaryScopeModule:
+		return []byte("module"), nil
+	case BoundaryScopeDirectory:
+		return []byte("directory"), nil
+	default:
+		return nil, fmt.Errorf("cannot marshal invalid BoundaryScope(%d)", s)
+	}
+}
@@ -0,0 +1,155 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sirkon/cerrful/internal/tracing"
+)
+
+// FileName is the name of the configuration file.
+const FileName = "cerrful.yaml"
+
+// Config represents cerrful configuration.
+type Config struct {
+	Sentinels         []tracing.Reference
+	Constructors      []tracing.Reference
+	Wrappers          []tracing.WrapSpec
+	Transparent       []tracing.Reference
+	NilErrors         []tracing.Reference
+	Loggers           []tracing.LoggerSpec
+	StructuredLoggers []string
+	UniquenessScope   UniquenessScope
+	Boundary          Boundary
+
+	// MessageMaxLength is the maximum length of error messages in runes, the default
+	// one is used if it is not set.
+	MessageMaxLength int
+
+	ForbiddenTerms ForbiddenTerms
+}
+
+// Find looks for the configuration file starting from the given directory
+// and walking up through its parents, so the file placed in a module root
+// covers all packages of the module. The walk goes on past the module root,
+// so a repository-level file covers nested modules as well.
+//
+// The nearest file wins: the one placed in a subdirectory replaces the one
+// of the module for packages of the subdirectory. Files are not merged.
+func Find(dir string) (path string, found bool) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+
+	for {
+		path = filepath.Join(dir, FileName)
+		if st, err := os.Stat(path); err == nil && !st.IsDir() {
+			return path, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Load reads and parses the configuration file.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config file: %w", err)
+	}
+
+	return Parse(path, data)
+}
+
+// Parse parses configuration data. The name is used for error reporting.
+func Parse(name string, data []byte) (*Config, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			// Empty file, nothing to configure.
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("%s: decode yaml: %w", name, err)
+	}
+
+	d := decoder{name: name}
+	cfg, err := d.config(doc.Content[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Apply registers configured entities in the engine. Built-in standard library
+// facilities are registered as well.
+func (c *Config) Apply(engine *tracing.ScrapEngine) {
+	registerStd(engine)
+
+	for _, name := range c.StructuredLoggers {
+		for _, spec := range loggerPresets[name] {
+			engine.RegisterLogger(spec.Ref, spec.Kind)
+		}
+	}
+
+	for _, ref := range c.Sentinels {
+		engine.RegisterIgnoreError(ref)
+	}
+	for _, ref := range c.Constructors {
+		engine.RegisterNew(ref)
+	}
+	for _, spec := range c.Wrappers {
+		engine.RegisterWrap(spec.Ref, spec.Kind)
+	}
+	for _, ref := range c.Transparent {
+		engine.RegisterTransparent(ref)
+	}
+	for _, ref := range c.NilErrors {
+		engine.RegisterNilError(ref)
+	}
+	for _, spec := range c.Loggers {
+		engine.RegisterLogger(spec.Ref, spec.Kind)
+	}
+
+	if c.MessageMaxLength > 0 {
+		engine.SetMessageMaxLength(c.MessageMaxLength)
+	}
+}
+
+// Layer returns the layer the package belongs to, see [Boundary]. Unless it is configured
+// explicitly, the boundary scope follows the uniqueness one: the whole module is a layer
+// for the module uniqueness scope and every package is for others.
+func (c *Config) Layer(module, path string) string {
+	b := c.Boundary
+	if b.Scope == 0 {
+		b.Scope = BoundaryScopePackage
+		if c.UniquenessScope == UniquenessScopeModule {
+			b.Scope = BoundaryScopeModule
+		}
+	}
+
+	return b.Layer(module, path)
+}
+
+// Presets returns names of supported structured-loggers presets.
+func Presets() []string {
+	res := make([]string, 0, len(loggerPresets))
+	for name := range loggerPresets {
+		res = append(res, name)
+	}
+	slices.Sort(res)
+
+	return res
+}
@@ -0,0 +1,447 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sirkon/cerrful/internal/tracing"
+)
+
+func TestLoadDefault(t *testing.T) {
+	path, found := Find(".")
+	if !found {
+		t.Fatal("default configuration file was expected to be found")
+	}
+	abs, err := filepath.Abs("../../" + FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != abs {
+		t.Fatalf("unexpected configuration file %s", path)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Config{
+		Sentinels: []tracing.Reference{
+			{Package: "io", Name: "EOF"},
+		},
+		Wrappers: []tracing.WrapSpec{
+			{
+				Ref:  tracing.Reference{Package: "github.com/sirkon/errors", Name: "Wrap"},
+				Kind: tracing.WrapKindErrors,
+			},
+			{
+				Ref:  tracing.Reference{Package: "github.com/sirkon/errors", Name: "Wrapf"},
+				Kind: tracing.WrapKindErrors,
+			},
+		},
+		Transparent: []tracing.Reference{
+			{Package: "github.com/sirkon/errors", Name: "Just"},
+		},
+		StructuredLoggers: []string{"zap", "zerolog", "slog", "testing"},
+		UniquenessScope:   UniquenessScopePackage,
+	}
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("unexpected configuration\n got: %+v\nwant: %+v", cfg, expected)
+	}
+}
+
+func TestParse(t *testing.T) {
+	const data = `
+wrappers:
+  - ref: '"gopkg.in/errs.v1".Annotatef'
+    kind: fmt
+  - ref:
+      package: example.com/errs
+      type: Error
+      name: Wrap
+  - ref: go.uber.org/multierr.Combine
+    kind: join
+loggers:
+  - ref: example.com/log.Logger.Error
+    kind: zap
+  - example.com/log.Printf
+constructors:
+  - example.com/errs.New
+nil-error-funcs:
+  - example.com/buf.Buffer.Write
+  - io.Closer.Close
+boundary:
+  scope: directory
+  depth: 2
+  layers:
+    - internal/storage/...
+    - name: transport
+      packages: [internal/http/..., internal/grpc/...]
+message-max-length: 80
+`
+	cfg, err := Parse("cerrful.yaml", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := &Config{
+		Constructors: []tracing.Reference{
+			{Package: "example.com/errs", Name: "New"},
+		},
+		Wrappers: []tracing.WrapSpec{
+			{
+				Ref:  tracing.Reference{Package: "gopkg.in/errs.v1", Name: "Annotatef"},
+				Kind: tracing.WrapKindFmt,
+			},
+			{
+				Ref:  tracing.Reference{Package: "example.com/errs", Type: "Error", Name: "Wrap"},
+				Kind: tracing.WrapKindErrors,
+			},
+			{
+				Ref:  tracing.Reference{Package: "go.uber.org/multierr", Name: "Combine"},
+				Kind: tracing.WrapKindJoin,
+			},
+		},
+		Loggers: []tracing.LoggerSpec{
+			{
+				Ref:  tracing.Reference{Package: "example.com/log", Type: "Logger", Name: "Error"},
+				Kind: tracing.LoggingKindZap,
+			},
+			{
+				Ref:  tracing.Reference{Package: "example.com/log", Name: "Printf"},
+				Kind: tracing.LoggingKindFormat,
+			},
+		},
+		NilErrors: []tracing.Reference{
+			{Package: "example.com/buf", Type: "Buffer", Name: "Write"},
+			{Package: "io", Type: "Closer", Name: "Close"},
+		},
+		Boundary: Boundary{
+			Scope: BoundaryScopeDirectory,
+			Depth: 2,
+			Groups: []BoundaryGroup{
+				{Patterns: []string{"internal/storage/..."}},
+				{Name: "transport", Patterns: []string{"internal/http/...", "internal/grpc/..."}},
+			},
+		},
+		MessageMaxLength: 80,
+	}
+	if !reflect.DeepEqual(cfg, expected) {
+		t.Errorf("unexpected configuration\n got: %+v\nwant: %+v", cfg, expected)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{
+			name: "unknown-key",
+			data: "sentinels: [io.EOF]\nwrapers: []\n",
+			err:  `cerrful.yaml:2:1: unknown key "wrapers", must be one of ["boundary" "constructors" "forbidden-terms" "loggers" "message-max-length" "nil-error-funcs" "sentinels" "structured-loggers" "transparent-error-funcs" "uniqueness-scope" "wrappers"]`,
+		},
+		{
+			name: "invalid-reference",
+			data: "sentinels:\n  - io.EOF\n  - io\n",
+			err:  `cerrful.yaml:3:5: reference must contain a name: "io"`,
+		},
+		{
+			name: "invalid-wrap-kind",
+			data: "wrappers:\n  - ref: errs.Wrap\n    kind: pkg\n",
+			err:  `cerrful.yaml:3:11: unknown kind "pkg" of wrap`,
+		},
+		{
+			name: "unknown-reference-key",
+			data: "transparent-error-funcs:\n  - package: errs\n    func: Just\n",
+			err:  `cerrful.yaml:3:5: unknown key "func", must be one of ["name" "package" "type"]`,
+		},
+		{
+			name: "unknown-structured-logger",
+			data: "structured-loggers: [zap, logrus]\n",
+			err:  `cerrful.yaml:1:27: unknown structured logger "logrus", must be one of ["slog" "testing" "zap" "zerolog"]`,
+		},
+		{
+			name: "invalid-scope",
+			data: "uniqueness-scope: [package]\n",
+			err:  `cerrful.yaml:1:19: scalar value expected`,
+		},
+		{
+			name: "invalid-boundary-depth",
+			data: "boundary:\n  scope: directory\n  depth: 0\n",
+			err:  `cerrful.yaml:3:10: positive directory depth expected, got "0"`,
+		},
+		{
+			name: "empty-boundary-layer",
+			data: "boundary:\n  layers:\n    - name: storage\n",
+			err:  `cerrful.yaml:3:7: missing layer packages`,
+		},
+		{
+			name: "invalid-message-max-length",
+			data: "message-max-length: short\n",
+			err:  `cerrful.yaml:1:21: positive message length expected, got "short"`,
+		},
+		{
+			name: "forbidden-term-without-reason",
+			data: "forbidden-terms:\n  - word: oops\n",
+			err:  `cerrful.yaml:2:5: missing forbidden term reason`,
+		},
+		{
+			name: "forbidden-term-word-and-regex",
+			data: "forbidden-terms:\n  - word: oops\n    regex: oops\n    reason: noise\n",
+			err:  `cerrful.yaml:3:12: either word or regex expected`,
+		},
+		{
+			name: "invalid-forbidden-term-regex",
+			data: "forbidden-terms:\n  - regex: '(oops'\n    reason: noise\n",
+			err:  "cerrful.yaml:2:12: error parsing regexp: missing closing ): `(oops`",
+		},
+		{
+			name: "invalid-terms-mode",
+			data: "forbidden-terms:\n  mode: merge\n",
+			err:  `cerrful.yaml:2:9: unknown terms mode "merge"`,
+		},
+		{
+			name: "terms-override-without-packages",
+			data: "forbidden-terms:\n  overrides:\n    - mode: replace\n",
+			err:  `cerrful.yaml:3:7: missing override packages`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse("cerrful.yaml", []byte(tt.data))
+			if err == nil {
+				t.Fatal("error was expected")
+			}
+			if err.Error() != tt.err {
+				t.Errorf("unexpected error\n got: %s\nwant: %s", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestLayer(t *testing.T) {
+	const module = "example.com/app"
+	groups := []BoundaryGroup{
+		{Patterns: []string{"internal/storage/..."}},
+		{Name: "transport", Patterns: []string{"example.com/app/internal/http/...", "internal/grpc"}},
+	}
+
+	tests := []struct {
+		name   string
+		cfg    Config
+		module string
+		path   string
+		want   string
+	}{
+		{
+			name: "default",
+			path: "example.com/app/internal/service",
+			want: "example.com/app/internal/service",
+		},
+		{
+			name:   "uniqueness-scope",
+			cfg:    Config{UniquenessScope: UniquenessScopeModule},
+			module: module,
+			path:   "example.com/app/internal/service",
+			want:   module,
+		},
+		{
+			name:   "other-module",
+			cfg:    Config{Boundary: Boundary{Scope: BoundaryScopeModule}},
+			module: module,
+			path:   "example.com/lib",
+			want:   "example.com/lib",
+		},
+		{
+			name:   "directory",
+			cfg:    Config{Boundary: Boundary{Scope: BoundaryScopeDirectory}},
+			module: module,
+			path:   "example.com/app/internal/service/users",
+			want:   "example.com/app/internal",
+		},
+		{
+			name:   "directory-depth",
+			cfg:    Config{Boundary: Boundary{Scope: BoundaryScopeDirectory, Depth: 2}},
+			module: module,
+			path:   "example.com/app/internal/service/users",
+			want:   "example.com/app/internal/service",
+		},
+		{
+			name:   "directory-root",
+			cfg:    Config{Boundary: Boundary{Scope: BoundaryScopeDirectory}},
+			module: module,
+			path:   module,
+			want:   module,
+		},
+		{
+			name:   "relative-group",
+			cfg:    Config{Boundary: Boundary{Groups: groups}},
+			module: module,
+			path:   "example.com/app/internal/storage/pg",
+			want:   "internal/storage/...",
+		},
+		{
+			name:   "named-group",
+			cfg:    Config{Boundary: Boundary{Groups: groups}},
+			module: module,
+			path:   "example.com/app/internal/http",
+			want:   "transport",
+		},
+		{
+			name:   "exact-pattern",
+			cfg:    Config{Boundary: Boundary{Groups: groups}},
+			module: module,
+			path:   "example.com/app/internal/grpc/server",
+			want:   "example.com/app/internal/grpc/server",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Layer(tt.module, tt.path); got != tt.want {
+				t.Errorf("unexpected layer %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if path, found := Find(nested); found && filepath.Dir(path) != root {
+		// The temporary directory may live under some directory having its own configuration.
+		t.Logf("configuration outside of the test tree found: %s", path)
+	}
+
+	if err := os.WriteFile(filepath.Join(root, FileName), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, found := Find(nested)
+	if !found {
+		t.Fatal("configuration file was expected to be found")
+	}
+	if path != filepath.Join(root, FileName) {
+		t.Errorf("unexpected configuration file %s", path)
+	}
+}
+
+func TestFindOverride(t *testing.T) {
+	root := t.TempDir()
+	nested := filepath.Join(root, "a", "b")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{root, filepath.Join(root, "a")} {
+		if err := os.WriteFile(filepath.Join(dir, FileName), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The file of a subdirectory replaces the one of the module.
+	path, found := Find(nested)
+	if !found {
+		t.Fatal("configuration file was expected to be found")
+	}
+	if path != filepath.Join(root, "a", FileName) {
+		t.Errorf("unexpected configuration file %s", path)
+	}
+}
+
+func TestFindAboveModuleRoot(t *testing.T) {
+	root := t.TempDir()
+	module := filepath.Join(root, "services", "app")
+	nested := filepath.Join(module, "internal", "pkg")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(module, "go.mod"), []byte("module example.com/app\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, FileName), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The repository-level file covers nested modules.
+	path, found := Find(nested)
+	if !found {
+		t.Fatal("configuration file was expected to be found")
+	}
+	if path != filepath.Join(root, FileName) {
+		t.Errorf("unexpected configuration file %s", path)
+	}
+}
+
+func TestForbiddenTermsOf(t *testing.T) {
+	const module = "example.com/app"
+	const data = `
+forbidden-terms:
+  terms:
+    - word: failed to
+      reason: noise
+      replacement: ""
+  overrides:
+    - packages: [internal/legacy/...]
+      mode: replace
+      terms:
+        - regex: '(?i)\blegacy\b'
+          reason: everything is legacy here
+    - packages: [internal/legacy/db]
+      terms:
+        - word: db
+          reason: name the database
+          replacement: database
+`
+	cfg, err := Parse("cerrful.yaml", []byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{
+			path: "example.com/app/internal/service",
+			want: []string{"err", "couldn't", "can't", "failed to"},
+		},
+		{
+			path: "example.com/app/internal/legacy",
+			want: []string{`(?i)\blegacy\b`},
+		},
+		{
+			path: "example.com/app/internal/legacy/db",
+			want: []string{`(?i)\blegacy\b`, "db"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var got []string
+			for _, term := range cfg.ForbiddenTerms.Of(module, tt.path) {
+				got = append(got, term.Term)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unexpected terms %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	terms := cfg.ForbiddenTerms.Of(module, "example.com/app/internal/legacy/db")
+	if replacement := terms[1].Replacement; replacement == nil || *replacement != "database" {
+		t.Errorf("unexpected replacement of %q", terms[1].Term)
+	}
+	if !terms[1].Pattern.MatchString("open DB connection") || terms[1].Pattern.MatchString("open dbx") {
+		t.Errorf("unexpected matches of the word pattern %s", terms[1].Pattern)
+	}
+}
@@ -0,0 +1,487 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/sirkon/cerrful/internal/tracing"
+)
+
+// decoder walks YAML nodes manually to reject unknown keys and to report
+// errors with a position of the node they originate from.
+type decoder struct {
+	name string
+}
+
+func (d *decoder) config(node *yaml.Node) (*Config, error) {
+	var cfg Config
+	err := d.mapping(node, map[string]func(*yaml.Node) error{
+		"sentinels": func(n *yaml.Node) error {
+			return d.sequence(n, "sentinel", func(n *yaml.Node) error {
+				ref, err := d.reference(n)
+				cfg.Sentinels = append(cfg.Sentinels, ref)
+				return err
+			})
+		},
+		"constructors": func(n *yaml.Node) error {
+			return d.sequence(n, "constructor", func(n *yaml.Node) error {
+				ref, err := d.reference(n)
+				cfg.Constructors = append(cfg.Constructors, ref)
+				return err
+			})
+		},
+		"wrappers": func(n *yaml.Node) error {
+			return d.sequence(n, "wrapper", func(n *yaml.Node) error {
+				spec, err := d.wrapper(n)
+				cfg.Wrappers = append(cfg.Wrappers, spec)
+				return err
+			})
+		},
+		"transparent-error-funcs": func(n *yaml.Node) error {
+			return d.sequence(n, "transparent error function", func(n *yaml.Node) error {
+				ref, err := d.reference(n)
+				cfg.Transparent = append(cfg.Transparent, ref)
+				return err
+			})
+		},
+		"nil-error-funcs": func(n *yaml.Node) error {
+			return d.sequence(n, "nil error function", func(n *yaml.Node) error {
+				ref, err := d.reference(n)
+				cfg.NilErrors = append(cfg.NilErrors, ref)
+				return err
+			})
+		},
+		"loggers": func(n *yaml.Node) error {
+			return d.sequence(n, "logger", func(n *yaml.Node) error {
+				spec, err := d.logger(n)
+				cfg.Loggers = append(cfg.Loggers, spec)
+				return err
+			})
+		},
+		"structured-loggers": func(n *yaml.Node) error {
+			return d.sequence(n, "structured logger", func(n *yaml.Node) error {
+				if err := d.scalar(n); err != nil {
+					return err
+				}
+				if _, ok := loggerPresets[n.Value]; !ok {
+					return d.errorf(n, "unknown structured logger %q, must be one of %q", n.Value, Presets())
+				}
+
+				cfg.StructuredLoggers = append(cfg.StructuredLoggers, n.Value)
+				return nil
+			})
+		},
+		"uniqueness-scope": func(n *yaml.Node) error {
+			return d.text(n, &cfg.UniquenessScope)
+		},
+		"boundary": func(n *yaml.Node) (err error) {
+			cfg.Boundary, err = d.boundary(n)
+			return err
+		},
+		"message-max-length": func(n *yaml.Node) error {
+			if err := d.scalar(n); err != nil {
+				return err
+			}
+
+			length, err := strconv.Atoi(n.Value)
+			if err != nil || length < 1 {
+				return d.errorf(n, "positive message length expected, got %q", n.Value)
+			}
+			cfg.MessageMaxLength = length
+			return nil
+		},
+		"forbidden-terms": func(n *yaml.Node) (err error) {
+			cfg.ForbiddenTerms, err = d.forbiddenTerms(n)
+			return err
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &cfg, nil
+}
+
+func (d *decoder) wrapper(node *yaml.Node) (tracing.WrapSpec, error) {
+	spec := tracing.WrapSpec{
+		Kind: tracing.WrapKindErrors,
+	}
+
+	if node.Kind == yaml.ScalarNode {
+		return spec, d.text(node, &spec.Ref)
+	}
+
+	var hasRef bool
+	err := d.mapping(node, map[string]func(*yaml.Node) error{
+		"ref": func(n *yaml.Node) (err error) {
+			hasRef = true
+			spec.Ref, err = d.reference(n)
+			return err
+		},
+		"kind": func(n *yaml.Node) error {
+			return d.text(n, &spec.Kind)
+		},
+	})
+	if err != nil {
+		return spec, err
+	}
+	if !hasRef {
+		return spec, d.errorf(node, "missing wrapper ref")
+	}
+
+	return spec, nil
+}
+
+func (d *decoder) logger(node *yaml.Node) (tracing.LoggerSpec, error) {
+	spec := tracing.LoggerSpec{
+		Kind: tracing.LoggingKindFormat,
+	}
+
+	if node.Kind == yaml.ScalarNode {
+		return spec, d.text(node, &spec.Ref)
+	}
+
+	var hasRef bool
+	err := d.mapping(node, map[string]func(*yaml.Node) error{
+		"ref": func(n *yaml.Node) (err error) {
+			hasRef = true
+			spec.Ref, err = d.reference(n)
+			return err
+		},
+		"kind": func(n *yaml.Node) error {
+			return d.text(n, &spec.Kind)
+		},
+	})
+	if err != nil {
+		return spec, err
+	}
+	if !hasRef {
+		return spec, d.errorf(node, "missing logger ref")
+	}
+
+	return spec, nil
+}
+
+// boundary decodes either a boundary scope or a mapping of boundary settings.
+func (d *decoder) boundary(node *yaml.Node) (Boundary, error) {
+	var res Boundary
+	if node.Kind == yaml.ScalarNode {
+		return res, d.text(node, &res.Scope)
+	}
+
+	err := d.mapping(node, map[string]func(*yaml.Node) error{
+		"scope": func(n *yaml.Node) error {
+			return d.text(n, &res.Scope)
+		},
+		"depth": func(n *yaml.Node) error {
+			if err := d.scalar(n); err != nil {
+				return err
+			}
+
+			depth, err := strconv.Atoi(n.Value)
+			if err != nil || depth < 1 {
+				return d.errorf(n, "positive directory depth expected, got %q", n.Value)
+			}
+			res.Depth = depth
+			return nil
+		},
+		"layers": func(n *yaml.Node) error {
+			return d.sequence(n, "layer", func(n *yaml.Node) error {
+				group, err := d.boundaryGroup(n)
+				res.Groups = append(res.Groups, group)
+				return err
+			})
+		},
+	})
+
+	return res, err
+}
+
+// boundaryGroup decodes either a single package pattern or a named list of them.
+func (d *decoder) boundaryGroup(node *yaml.Node) (BoundaryGroup, error) {
+	var res BoundaryGroup
+	if node.Kind == yaml.ScalarNode {
+		res.Patterns = []string{node.Value}
+		return res, nil
+	}
+
+	err := d.mapping(node, map[string]func(*yaml.Node) error{
+		"name": func(n *yaml.Node) error {
+			return d.string(n, &res.Name)
+		},
+		"packages": func(n *yaml.Node) error {
+			return d.sequence(n, "package pattern", func(n *yaml.Node) error {
+				var pattern string
+				if err := d.string(n, &pattern); err != nil {
+					return err
+				}
+
+				res.Patterns = append(res.Patterns, pattern)
+				return nil
+			})
+		},
+	})
+	if err != nil {
+		return res, err
+	}
+	if len(res.Patterns) == 0 {
+		return res, d.errorf(node, "missing layer packages")
+	}
+
+	return res, nil
+}
+
+// forbiddenTerms decodes either a list of terms extending built-in ones or a mapping of terms
+// settings.
+func (d *decoder) forbiddenTerms(node *yaml.Node) (ForbiddenTerms, error) {
+	if node.Kind == yaml.SequenceNode {
+		// A list is a shortcut for the terms key.
+		key := &yaml.Node{Kind: yaml.ScalarNode, Value: "terms"}
+		node = &yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{key, node}}
+	}
+
+	var res ForbiddenTerms
+	err := d.mapping(node, map[string]func(*yaml.Node) error{
+		"mode": func(n *yaml.Node) error {
+			return d.text(n, &res.Mode)
+		},
+		"terms": func(n *yaml.Node) (err error) {
+			res.Terms, err = d.terms(n)
+			return err
+		},
+		"overrides": func(n *yaml.Node) error {
+			return d.sequence(n, "terms override", func(n *yaml.Node) error {
+				override, err := d.termsOverride(n)
+				res.Overrides = append(res.Overrides, override)
+				return err
+			})
+		},
+	})
+
+	return res, err
+}
+
+func (d *decoder) termsOverride(node *yaml.Node) (TermsOverride, error) {
+	var res TermsOverride
+	err := d.mapping(node, map[string]func(*yaml.Node) error{
+		"packages": func(n *yaml.Node) error {
+			return d.sequence(n, "package pattern", func(n *yaml.Node) error {
+				var pattern string
+				if err := d.string(n, &pattern); err != nil {
+					return err
+				}
+
+				res.Patterns = append(res.Patterns, pattern)
+				return nil
+			})
+		},
+		"mode": func(n *yaml.Node) error {
+			return d.text(n, &res.Mode)
+		},
+		"terms": func(n *yaml.Node) (err error) {
+			res.Terms, err = d.terms(n)
+			return err
+		},
+	})
+	if err != nil {
+		return res, err
+	}
+	if len(res.Patterns) == 0 {
+		return res, d.errorf(node, "missing override packages")
+	}
+
+	return res, nil
+}
+
+func (d *decoder) terms(node *yaml.Node) ([]tracing.ForbiddenTerm, error) {
+	var res []tracing.ForbiddenTerm
+	err := d.sequence(node, "forbidden term", func(n *yaml.Node) error {
+		term, err := d.term(n)
+		res = append(res, term)
+		return err
+	})
+
+	return res, err
+}
+
+// term decodes a forbidden term: either a word or a regular expression with a reason
+// and an optional replacement.
+func (d *decoder) term(node *yaml.Node) (tracing.ForbiddenTerm, error) {
+	var res tracing.ForbiddenTerm
+	err := d.mapping(node, map[string]func(*yaml.Node) error{
+		"word": func(n *yaml.Node) error {
+			if err := d.string(n, &res.Term); err != nil {
+				return err
+			}
+			if res.Pattern != nil {
+				return d.errorf(n, "either word or regex expected")
+			}
+			if strings.TrimSpace(res.Term) == "" {
+				return d.errorf(n, "empty forbidden word")
+			}
+
+			res.Pattern = tracing.WordPattern(res.Term)
+			return nil
+		},
+		"regex": func(n *yaml.Node) error {
+			if err := d.string(n, &res.Term); err != nil {
+				return err
+			}
+			if res.Pattern != nil {
+				return d.errorf(n, "either word or regex expected")
+			}
+
+			pattern, err := regexp.Compile(res.Term)
+			if err != nil {
+				return d.wrap(n, err)
+			}
+			res.Pattern = pattern
+			return nil
+		},
+		"reason": func(n *yaml.Node) error {
+			return d.string(n, &res.Reason)
+		},
+		"replacement": func(n *yaml.Node) error {
+			var replacement string
+			if err := d.string(n, &replacement); err != nil {
+				return err
+			}
+
+			res.Replacement = &replacement
+			return nil
+		},
+	})
+	if err != nil {
+		return res, err
+	}
+	if res.Pattern == nil {
+		return res, d.errorf(node, "missing forbidden term word or regex")
+	}
+	if res.Reason == "" {
+		return res, d.errorf(node, "missing forbidden term reason")
+	}
+
+	return res, nil
+}
+
+// reference decodes either a text reference or a mapping of its components.
+func (d *decoder) reference(node *yaml.Node) (tracing.Reference, error) {
+	var ref tracing.Reference
+	if node.Kind == yaml.ScalarNode {
+		return ref, d.text(node, &ref)
+	}
+
+	err := d.mapping(node, map[string]func(*yaml.Node) error{
+		"package": func(n *yaml.Node) error {
+			return d.string(n, &ref.Package)
+		},
+		"type": func(n *yaml.Node) error {
+			return d.string(n, &ref.Type)
+		},
+		"name": func(n *yaml.Node) error {
+			return d.string(n, &ref.Name)
+		},
+	})
+	if err != nil {
+		return ref, err
+	}
+
+	// Validate components in the same way the text form does.
+	text, err := ref.MarshalText()
+	if err != nil {
+		return ref, d.wrap(node, err)
+	}
+	var check tracing.Reference
+	if err := check.UnmarshalText(text); err != nil {
+		return ref, d.wrap(node, err)
+	}
+
+	return ref, nil
+}
+
+func (d *decoder) mapping(node *yaml.Node, fields map[string]func(*yaml.Node) error) error {
+	if node.Kind != yaml.MappingNode {
+		return d.errorf(node, "mapping expected")
+	}
+
+	seen := map[string]bool{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		handler, ok := fields[key.Value]
+		if !ok {
+			keys := make([]string, 0, len(fields))
+			for k := range fields {
+				keys = append(keys, k)
+			}
+			slices.Sort(keys)
+			return d.errorf(key, "unknown key %q, must be one of %q", key.Value, keys)
+		}
+		if seen[key.Value] {
+			return d.errorf(key, "duplicate key %q", key.Value)
+		}
+		seen[key.Value] = true
+
+		if err := handler(value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *decoder) sequence(node *yaml.Node, what string, item func(*yaml.Node) error) error {
+	if node.Kind != yaml.SequenceNode {
+		return d.errorf(node, "list of %ss expected", what)
+	}
+
+	for _, n := range node.Content {
+		if err := item(n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (d *decoder) string(node *yaml.Node, dst *string) error {
+	if err := d.scalar(node); err != nil {
+		return err
+	}
+
+	*dst = node.Value
+	return nil
+}
+
+func (d *decoder) text(node *yaml.Node, dst interface{ UnmarshalText([]byte) error }) error {
+	if err := d.scalar(node); err != nil {
+		return err
+	}
+
+	if err := dst.UnmarshalText([]byte(node.Value)); err != nil {
+		return d.wrap(node, err)
+	}
+
+	return nil
+}
+
+func (d *decoder) scalar(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return d.errorf(node, "scalar value expected")
+	}
+
+	return nil
+}
+
+func (d *decoder) errorf(node *yaml.Node, format string, a ...any) error {
+	return d.wrap(node, fmt.Errorf(format, a...))
+}
+
+func (d *decoder) wrap(node *yaml.Node, err error) error {
+	return fmt.Errorf("%s:%d:%d: %w", d.name, node.Line, node.Column, err)
+}
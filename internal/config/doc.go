@@ -0,0 +1,59 @@
+// Package config loads cerrful configuration from cerrful.yaml files.
+//
+// The configuration describes project-specific error facilities — sentinels,
+// constructors, wrappers, transparent helpers, calls whose errors can be dropped
+// and loggers — and is applied on top of built-in standard library knowledge:
+//
+//	sentinels:
+//	  - io.EOF
+//	constructors:
+//	  - github.com/sirkon/errors.New
+//	wrappers:
+//	  - github.com/sirkon/errors.Wrap   # errors-style wrap by default
+//	  - ref: example.com/errs.Annotatef
+//	    kind: fmt
+//	transparent-error-funcs:
+//	  - package: github.com/sirkon/errors
+//	    name: Just
+//	nil-error-funcs:                    # errors of these are documented to be nil
+//	  - example.com/buf.Buffer.Write
+//	loggers:
+//	  - ref: '"example.com/log".Logger.Error'
+//	    kind: format
+//	structured-loggers:
+//	  - zap
+//	  - slog
+//	uniqueness-scope: package           # function, package or module
+//	message-max-length: 64              # in runes
+//	forbidden-terms:                    # a list of terms or a mapping
+//	  mode: extend                      # extend or replace built-in terms
+//	  terms:
+//	    - word: failed to               # or regex
+//	      reason: an error is a failure already
+//	      replacement: ""               # no fix is suggested if not set
+//	  overrides:
+//	    - packages: [internal/legacy/...]
+//	      mode: replace
+//	      terms: []
+//	boundary:                           # layers errors must be annotated between
+//	  scope: directory                  # package, module or directory
+//	  depth: 2
+//	  layers:
+//	    - internal/storage/...
+//	    - name: transport
+//	      packages: [internal/http/..., internal/grpc/...]
+//
+// Annotation messages must be unique within the uniqueness scope, messages are compared
+// ignoring case, whitespace, punctuation and stop words like "the". It is the package if
+// not set.
+//
+// The boundary follows the uniqueness scope if it is not set: the whole module is a single
+// layer for the module scope and every package is a layer of its own otherwise.
+//
+// References can be written either in the canonical `"pkg/path".Type.Name` form
+// or as `pkg/path.Type.Name` shorthand when the last package path element has no dots.
+// Methods of interfaces can be referred too: an entry like `io.Closer.Close` covers
+// calls through the interface as well as methods of every type implementing it.
+// Unknown keys are rejected and every error points at the line and column of
+// the offending node.
+package config
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/sirkon/cerrful/internal/tracing"
+)
+
+// registerStd registers standard library error facilities.
+func registerStd(engine *tracing.ScrapEngine) {
+	engine.RegisterNew(tracing.Reference{Package: "errors", Name: "New"})
+	engine.RegisterWrap(tracing.Reference{Package: "fmt", Name: "Errorf"}, tracing.WrapKindFmt)
+	engine.RegisterWrap(tracing.Reference{Package: "errors", Name: "Join"}, tracing.WrapKindJoin)
+
+	for _, name := range []string{"Print", "Printf", "Println"} {
+		engine.RegisterLogger(tracing.Reference{Package: "fmt", Name: name}, tracing.LoggingKindFormat)
+	}
+	for _, name := range []string{
+		"Print", "Printf", "Println",
+		"Fatal", "Fatalf", "Fatalln",
+		"Panic", "Panicf", "Panicln",
+	} {
+		engine.RegisterLogger(tracing.Reference{Package: "log", Name: name}, tracing.LoggingKindFormat)
+		engine.RegisterLogger(tracing.Reference{Package: "log", Type: "Logger", Name: name}, tracing.LoggingKindFormat)
+	}
+
+	engine.RegisterIgnoreError(tracing.Reference{Package: "io", Name: "EOF"})
+
+	// Errors of these are either documented to be always nil or never checked in practice.
+	for _, name := range []string{"Print", "Printf", "Println", "Fprint", "Fprintf", "Fprintln"} {
+		engine.RegisterNilError(tracing.Reference{Package: "fmt", Name: name})
+	}
+	engine.RegisterNilError(tracing.Reference{Package: "net/http", Type: "ResponseWriter", Name: "Write"})
+	for _, typ := range []string{"bytes.Buffer", "strings.Builder"} {
+		pkg, typ, _ := strings.Cut(typ, ".")
+		for _, name := range []string{"Write", "WriteByte", "WriteRune", "WriteString"} {
+			engine.RegisterNilError(tracing.Reference{Package: pkg, Type: typ, Name: name})
+		}
+	}
+	for _, typ := range []string{"Hash", "Hash32", "Hash64"} {
+		engine.RegisterNilError(tracing.Reference{Package: "hash", Type: typ, Name: "Write"})
+	}
+}
+
+// loggerPresets are logger sets enabled with structured-loggers.
+var loggerPresets = map[string][]tracing.LoggerSpec{
+	"zap": append(
+		presetMethods(
+			"go.uber.org/zap",
+			tracing.LoggingKindZap,
+			[]string{"Logger"},
+			"Debug", "Info", "Warn", "Error", "DPanic", "Panic", "Fatal",
+		),
+		presetMethods(
+			"go.uber.org/zap",
+			tracing.LoggingKindFormat,
+			[]string{"SugaredLogger"},
+			"Debugf", "Infof", "Warnf", "Errorf", "DPanicf", "Panicf", "Fatalf",
+		)...,
+	),
+	"zerolog": presetMethods(
+		"github.com/rs/zerolog",
+		tracing.LoggingKindZeroLog,
+		[]string{"Event"},
+		"Msg", "Msgf", "Send",
+	),
+	"slog": presetMethods(
+		"log/slog",
+		tracing.LoggingKindSlog,
+		[]string{"", "Logger"},
+		"Debug", "Info", "Warn", "Error",
+		"DebugContext", "InfoContext", "WarnContext", "ErrorContext",
+		"Log", "LogAttrs",
+	),
+	"testing": presetMethods(
+		"testing",
+		tracing.LoggingKindFormat,
+		// Logging methods of T, B and F are all promoted from this one.
+		[]string{"common"},
+		"Error", "Errorf", "Fatal", "Fatalf", "Log", "Logf", "Skip", "Skipf",
+	),
+}
+
+func presetMethods(pkg string, kind tracing.LoggingKind, types []string, names ...string) []tracing.LoggerSpec {
+	var res []tracing.LoggerSpec
+	for _, typ := range types {
+		for _, name := range names {
+			res = append(res, tracing.LoggerSpec{
+				Ref: tracing.Reference{
+					Package: pkg,
+					Type:    typ,
+					Name:    name,
+				},
+				Kind: kind,
+			})
+		}
+	}
+
+	return res
+}
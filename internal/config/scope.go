@@ -0,0 +1,56 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+)
+
+// UniquenessScope defines an area where annotation messages are expected to be unique.
+type UniquenessScope int
+
+const (
+	_ UniquenessScope = iota
+	UniquenessScopeFunction
+	UniquenessScopePackage
+	UniquenessScopeModule
+)
+
+func (s UniquenessScope) String() string {
+	v, err := s.MarshalText()
+	if err != nil {
+		return fmt.Sprintf("uniqueness-scope-invalid(%d)", s)
+	}
+
+	return string(v)
+}
+
+var _ encoding.TextUnmarshaler = (*UniquenessScope)(nil)
+
+func (s *UniquenessScope) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "function":
+		*s = UniquenessScopeFunction
+		return nil
+	case "package":
+		*s = UniquenessScopePackage
+		return nil
+	case "module":
+		*s = UniquenessScopeModule
+		return nil
+	default:
+		return fmt.Errorf("unknown uniqueness scope %q", b)
+	}
+}
+
+func (s UniquenessScope) MarshalText() ([]byte, error) {
+	switch s {
+	case UniquenessScopeFunction:
+		return []byte("function"), nil
+	case UniquenessScopePackage:
+		return []byte("package"), nil
+	case UniquenessScopeModule:
+		return []byte("module"), nil
+	default:
+		return nil, fmt.Errorf("cannot marshal invalid UniquenessScope(%d)", s)
+	}
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+
+	"github.com/sirkon/cerrful/internal/tracing"
+)
+
+// ForbiddenTerms describes terms error messages must not contain.
+type ForbiddenTerms struct {
+	// Mode defines how Terms are combined with built-in ones, they extend them by default.
+	Mode  TermsMode
+	Terms []tracing.ForbiddenTerm
+
+	// Overrides change terms of packages matching their patterns. All overrides matching
+	// are applied in order.
+	Overrides []TermsOverride
+}
+
+// TermsOverride changes forbidden terms of some packages.
+type TermsOverride struct {
+	// Patterns of import paths of packages the override applies to, see [BoundaryGroup.Patterns].
+	Patterns []string
+
+	// Mode defines how Terms are combined with terms of the module.
+	Mode  TermsMode
+	Terms []tracing.ForbiddenTerm
+}
+
+// Of returns forbidden terms of the package. The module is the path of the module being
+// analyzed, see [Boundary.Layer].
+func (t ForbiddenTerms) Of(module, path string) []tracing.ForbiddenTerm {
+	res := t.Mode.apply(tracing.DefaultForbiddenTerms(), t.Terms)
+	for _, o := range t.Overrides {
+		for _, p := range o.Patterns {
+			if matchPattern(module, p, path) {
+				res = o.Mode.apply(res, o.Terms)
+				break
+			}
+		}
+	}
+
+	return res
+}
+
+// TermsMode defines how configured forbidden terms are combined with inherited ones.
+type TermsMode int
+
+const (
+	_ TermsMode = iota
+
+	// TermsModeExtend adds terms to inherited ones.
+	TermsModeExtend
+
+	// TermsModeReplace drops inherited terms.
+	TermsModeReplace
+)
+
+func (m TermsMode) apply(base, terms []tracing.ForbiddenTerm) []tracing.ForbiddenTerm {
+	if m == TermsModeReplace {
+		return terms
+	}
+
+	res := make([]tracing.ForbiddenTerm, 0, len(base)+len(terms))
+	res = append(res, base...)
+	return append(res, terms...)
+}
+
+func (m TermsMode) String() string {
+	v, err := m.MarshalText()
+	if err != nil {
+		return fmt.Sprintf("terms-mode-invalid(%d)", m)
+	}
+
+	return string(v)
+}
+
+var _ encoding.TextUnmarshaler = (*TermsMode)(nil)
+
+func (m *TermsMode) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "extend":
+		*m = TermsModeExtend
+		return nil
+	case "replace":
+		*m = TermsModeReplace
+		return nil
+	default:
+		return fmt.Errorf("unknown terms mode %q", b)
+	}
+}
+
+func (m TermsMode) MarshalText() ([]byte, error) {
+	switch m {
+	case TermsModeExtend:
+		return []byte("extend"), nil
+	case TermsModeReplace:
+		return []byte("replace"), nil
+	default:
+		return nil, fmt.Errorf("cannot marshal invalid TermsMode(%d)", m)
+	}
+}
@@ -0,0 +1,239 @@
+package tracing
+
+import (
+	"cmp"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/sirkon/cerrful/internal/cerrules"
+)
+
+// Annotation is a site of an annotation message.
+type Annotation struct {
+	Package string
+
+	// Function is the name of the function declaration the annotation belongs to, methods
+	// are named like "Type.Method". It is empty for package-level declarations.
+	Function string
+
+	Message string
+	Pos     token.Position
+
+	// at is the position of the annotation in the file set it was scrapped from.
+	at token.Pos
+}
+
+// PackageAnnotations lists annotation sites of a package. They are exported as
+// [golang.org/x/tools/go/analysis.Fact] to check uniqueness of messages across the module.
+type PackageAnnotations struct {
+	List []Annotation
+}
+
+// AFact makes package annotations analysis facts.
+func (*PackageAnnotations) AFact() {}
+
+func (a *PackageAnnotations) String() string {
+	return fmt.Sprintf("annotations(%d)", len(a.List))
+}
+
+// annotationStopWords are dropped from messages compared, they do not tell one site from another.
+var annotationStopWords = map[string]bool{
+	"a":    true,
+	"an":   true,
+	"the":  true,
+	"to":   true,
+	"of":   true,
+	"for":  true,
+	"in":   true,
+	"on":   true,
+	"at":   true,
+	"from": true,
+	"with": true,
+	"by":   true,
+	"into": true,
+}
+
+// normalizeAnnotation brings the message to the form it is compared in: lower case words
+// without surrounding punctuation and stop words, separated with a single space. So
+//
+//	"Get  the User:"
+//
+// is the same as "get user".
+func normalizeAnnotation(msg string) string {
+	var words []string
+	for _, word := range strings.Fields(strings.ToLower(msg)) {
+		word = strings.Trim(word, ",.;:!?")
+		if word == "" || annotationStopWords[word] {
+			continue
+		}
+		words = append(words, word)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// CheckAnnotations reports annotation messages of the package which are not unique. Local
+// annotations are of the package analyzed, foreign ones are of other packages of the scope
+// and only collide with local ones. Messages are only compared within functions if
+// perFunction is set.
+func CheckAnnotations(r *ReporterPhase, local, foreign []Annotation, perFunction bool) {
+	type key struct {
+		function string
+		message  string
+	}
+
+	groups := map[key][]Annotation{}
+	var keys []key
+	add := func(a Annotation) {
+		k := key{message: normalizeAnnotation(a.Message)}
+		if k.message == "" {
+			return
+		}
+		if perFunction {
+			k.function = a.Package + "." + a.Function
+		}
+
+		if _, ok := groups[k]; !ok {
+			keys = append(keys, k)
+		}
+		groups[k] = append(groups[k], a)
+	}
+	for _, a := range local {
+		add(a)
+	}
+	if !perFunction {
+		for _, a := range foreign {
+			add(a)
+		}
+	}
+
+	pkg := ""
+	if len(local) > 0 {
+		pkg = local[0].Package
+	}
+
+	for _, k := range keys {
+		sites := groups[k]
+		if len(sites) < 2 {
+			continue
+		}
+
+		slices.SortFunc(sites, compareAnnotations)
+		for i, site := range sites {
+			if site.Package != pkg {
+				continue
+			}
+
+			others := slices.Delete(slices.Clone(sites), i, i+1)
+			var related []ReportRelated
+			for _, other := range others {
+				related = append(related, ReportRelated{
+					Pos:     other.Pos,
+					Message: fmt.Sprintf("annotation message %q is used here as well", other.Message),
+				})
+			}
+
+			r.Report(
+				cerrules.AnnotationMessageMustBeUnique(),
+				annotationCollision(pkg, site, others),
+				site.Pos,
+				related...,
+			)
+		}
+	}
+}
+
+// annotationCollision describes the collision of the annotation with other sites. Their
+// messages are quoted as well if any of them differs, they only collide after normalization:
+//
+//	annotation message "do" is not unique, it is also used at a.go:25:10
+//	annotation message "lost hash of %v" collides with "lost hash %v" at tile.go:427:9 after normalization
+func annotationCollision(pkg string, site Annotation, others []Annotation) string {
+	normalized := slices.ContainsFunc(others, func(other Annotation) bool {
+		return other.Message != site.Message
+	})
+
+	places := make([]string, len(others))
+	for i, other := range others {
+		places[i] = annotationPlace(pkg, other)
+		if normalized {
+			places[i] = fmt.Sprintf("%q at %s", other.Message, places[i])
+		}
+	}
+
+	if normalized {
+		return fmt.Sprintf(
+			"annotation message %q collides with %s after normalization",
+			site.Message,
+			strings.Join(places, ", "),
+		)
+	}
+
+	return fmt.Sprintf(
+		"annotation message %q is not unique, it is also used at %s",
+		site.Message,
+		strings.Join(places, ", "),
+	)
+}
+
+// annotationPlace names the place of the annotation for messages: file name with line and
+// column, the file name is prefixed with the package path for sites of other packages.
+func annotationPlace(pkg string, a Annotation) string {
+	name := filepath.Base(a.Pos.Filename)
+	if a.Package != pkg {
+		name = a.Package + "/" + name
+	}
+
+	return fmt.Sprintf("%s:%d:%d", name, a.Pos.Line, a.Pos.Column)
+}
+
+func compareAnnotations(a, b Annotation) int {
+	return cmp.Or(
+		strings.Compare(a.Package, b.Package),
+		strings.Compare(a.Pos.Filename, b.Pos.Filename),
+		cmp.Compare(a.Pos.Offset, b.Pos.Offset),
+	)
+}
+
+// setAnnotationFunctions sets names of functions annotations of the file belong to.
+func setAnnotationFunctions(file *ast.File, annotations []Annotation) {
+	for i := range annotations {
+		a := &annotations[i]
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if ok && fn.Pos() <= a.at && a.at < fn.End() {
+				a.Function = declName(fn)
+				break
+			}
+		}
+	}
+}
+
+// declName returns the name of the function declaration, methods are named like "Type.Method".
+func declName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+
+	typ := fn.Recv.List[0].Type
+	for {
+		switch v := typ.(type) {
+		case *ast.StarExpr:
+			typ = v.X
+		case *ast.IndexExpr:
+			typ = v.X
+		case *ast.IndexListExpr:
+			typ = v.X
+		case *ast.ParenExpr:
+			typ = v.X
+		case *ast.Ident:
+			return v.Name + "." + fn.Name.Name
+		default:
+			return fn.Name.Name
+		}
+	}
+}
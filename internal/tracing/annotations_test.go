@@ -0,0 +1,89 @@
+package tracing
+
+import (
+	"go/token"
+	"testing"
+)
+
+func TestNormalizeAnnotation(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{msg: "get user", want: "get user"},
+		{msg: "Get  the User:", want: "get user"},
+		{msg: " read\tfrom the file ", want: "read file"},
+		{msg: "open, then close", want: "open then close"},
+		{msg: "the", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			if got := normalizeAnnotation(tt.msg); got != tt.want {
+				t.Errorf("normalizeAnnotation(%q) = %q, want %q", tt.msg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckAnnotations(t *testing.T) {
+	site := func(pkg, fn, msg string, line int) Annotation {
+		return Annotation{
+			Package:  pkg,
+			Function: fn,
+			Message:  msg,
+			Pos:      token.Position{Filename: "/src/" + pkg + "/file.go", Offset: line * 10, Line: line, Column: 2},
+		}
+	}
+
+	local := []Annotation{
+		site("a", "f", "get user", 10),
+		site("a", "g", "Get the user", 20),
+		site("a", "g", "get group", 30),
+		site("a", "g", "the", 40),
+		site("a", "g", "a", 50),
+	}
+	foreign := []Annotation{
+		site("b", "h", "get group", 5),
+		site("b", "h", "put group", 15),
+	}
+
+	tests := []struct {
+		name        string
+		perFunction bool
+		want        []string
+	}{
+		{
+			name: "package",
+			want: []string{
+				`annotation message "get user" collides with "Get the user" at file.go:20:2 after normalization`,
+				`annotation message "Get the user" collides with "get user" at file.go:10:2 after normalization`,
+				`annotation message "get group" is not unique, it is also used at b/file.go:5:2`,
+			},
+		},
+		{
+			name:        "function",
+			perFunction: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var r ReportEngine
+			CheckAnnotations(r.Phase(ReportState), local, foreign, tt.perFunction)
+
+			reports := r.Reports()
+			if len(reports) != len(tt.want) {
+				t.Fatalf("expected %d reports, got %d", len(tt.want), len(reports))
+			}
+			for i, rep := range reports {
+				if rep.Message != tt.want[i] {
+					t.Errorf("unexpected report %q, want %q", rep.Message, tt.want[i])
+				}
+				if len(rep.Related) != 1 {
+					t.Errorf("expected the other site related to %q, got %d", rep.Message, len(rep.Related))
+				}
+			}
+		})
+	}
+}
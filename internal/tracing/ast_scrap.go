@@ -3,11 +3,14 @@ package tracing
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
+	"slices"
 	"strconv"
 	"strings"
 
 	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
 
 	"github.com/sirkon/cerrful/internal/cerrules"
 	"github.com/sirkon/cerrful/internal/cir"
@@ -18,18 +21,38 @@ type ScrapEngine struct {
 	news          map[Reference]NewSpec
 	wraps         map[Reference]WrapSpec
 	loggers       map[Reference]LoggerSpec
+	transparent   map[Reference]TransparentSpec
+	nilErrors     map[Reference]NilErrorSpec
 	ignoredErrors map[Reference]IgnoredError
 
+	// methodRefs and ifaces cache method entries and their interfaces, see [ScrapEngine.specRef].
+	methodRefs []Reference
+	ifaces     map[Reference]*types.Interface
+
+	// annotations are sites of annotation messages scrapped so far.
+	annotations []Annotation
+
+	// messageMaxLength is the maximum length of error messages in runes.
+	messageMaxLength int
+
+	// forbiddenTerms are terms error messages must not contain.
+	forbiddenTerms []ForbiddenTerm
+
 	r *ReporterPhase
 }
 
 func NewScrapEngine(r *ReporterPhase) *ScrapEngine {
 	return &ScrapEngine{
-		news:          make(map[Reference]NewSpec),
-		wraps:         make(map[Reference]WrapSpec),
-		loggers:       make(map[Reference]LoggerSpec),
-		ignoredErrors: make(map[Reference]IgnoredError),
-		r:             r,
+		news:             make(map[Reference]NewSpec),
+		wraps:            make(map[Reference]WrapSpec),
+		loggers:          make(map[Reference]LoggerSpec),
+		transparent:      make(map[Reference]TransparentSpec),
+		nilErrors:        make(map[Reference]NilErrorSpec),
+		ignoredErrors:    make(map[Reference]IgnoredError),
+		ifaces:           make(map[Reference]*types.Interface),
+		messageMaxLength: DefaultMessageMaxLength,
+		forbiddenTerms:   DefaultForbiddenTerms(),
+		r:                r,
 	}
 }
 
@@ -37,24 +60,55 @@ func NewScrapEngine(r *ReporterPhase) *ScrapEngine {
 
 // RegisterWrap registers a wrap function.
 func (e *ScrapEngine) RegisterWrap(ref Reference, kind WrapKind) {
+	e.methodRefs = nil
 	e.wraps[ref] = WrapSpec{Ref: ref, Kind: kind}
 }
 
 // RegisterLogger registers a logger function.
 func (e *ScrapEngine) RegisterLogger(ref Reference, kind LoggingKind) {
+	e.methodRefs = nil
 	e.loggers[ref] = LoggerSpec{Ref: ref, Kind: kind}
 }
 
 // RegisterNew registers an error-constructor function.
 func (e *ScrapEngine) RegisterNew(ref Reference) {
+	e.methodRefs = nil
 	e.news[ref] = NewSpec{Ref: ref}
 }
 
+// RegisterTransparent registers a function passing its error argument through.
+func (e *ScrapEngine) RegisterTransparent(ref Reference) {
+	e.methodRefs = nil
+	e.transparent[ref] = TransparentSpec{Ref: ref}
+}
+
+// RegisterNilError registers a function whose error result can be dropped.
+func (e *ScrapEngine) RegisterNilError(ref Reference) {
+	e.methodRefs = nil
+	e.nilErrors[ref] = NilErrorSpec{Ref: ref}
+}
+
 // RegisterIgnoreError registers an error type to be ignored.
 func (e *ScrapEngine) RegisterIgnoreError(ref Reference) {
 	e.ignoredErrors[ref] = IgnoredError{Ref: ref}
 }
 
+// IsIgnoredError checks if the error is registered to be ignored.
+func (e *ScrapEngine) IsIgnoredError(ref Reference) bool {
+	_, ok := e.ignoredErrors[ref]
+	return ok
+}
+
+// SetMessageMaxLength sets the maximum length of error messages in runes.
+func (e *ScrapEngine) SetMessageMaxLength(n int) {
+	e.messageMaxLength = n
+}
+
+// Annotations returns sites of annotation messages of all files scrapped.
+func (e *ScrapEngine) Annotations() []Annotation {
+	return e.annotations
+}
+
 // --- Actual logic ---------------------------------------------------------------------------------------------------
 
 // Scrap traverses the file AST and records structural information
@@ -64,9 +118,13 @@ func (e *ScrapEngine) Scrap(
 	pass *analysis.Pass,
 	file *ast.File,
 ) {
-	// gotypes shortcuts
-	info := pass.TypesInfo
-	fset := pass.Fset
+	// Composite literals of &T{…} are recorded along with the address operation.
+	pointed := map[*ast.CompositeLit]bool{}
+
+	first := len(e.annotations)
+	defer func() {
+		setAnnotationFunctions(file, e.annotations[first:])
+	}()
 
 	// Walk the AST
 	ast.Inspect(file, func(n ast.Node) bool {
@@ -76,7 +134,7 @@ func (e *ScrapEngine) Scrap(
 		// 1. Function calls → wrap/new/log
 		// ---------------------------------------
 		case *ast.CallExpr:
-			e.scrapCall(ctx, pass, node)
+			e.scrapCall(ctx, pass, callFn(pass.TypesInfo, node), node)
 			return true
 
 		// ---------------------------------------
@@ -87,6 +145,10 @@ func (e *ScrapEngine) Scrap(
 			e.scrapAssign(ctx, pass, node)
 			return true
 
+		case *ast.ExprStmt:
+			e.scrapExprStmt(pass, node)
+			return true
+
 		// ---------------------------------------
 		// 3. Return statements
 		//    (may propagate ignored errors etc.)
@@ -113,6 +175,22 @@ func (e *ScrapEngine) Scrap(
 			e.scrapTypeSwitch(ctx, pass, node)
 			return true
 
+		// ---------------------------------------
+		// Typed errors: T{…} and &T{…}
+		// ---------------------------------------
+		case *ast.UnaryExpr:
+			if lit, ok := ast.Unparen(node.X).(*ast.CompositeLit); ok && node.Op == token.AND {
+				pointed[lit] = true
+				scrapTyped(ctx, pass, node)
+			}
+			return true
+
+		case *ast.CompositeLit:
+			if !pointed[node] {
+				scrapTyped(ctx, pass, node)
+			}
+			return true
+
 		default:
 			return true
 		}
@@ -125,15 +203,27 @@ func (e *ScrapEngine) scrapCall(
 	fn *Fn,
 	call *ast.CallExpr,
 ) {
+	// conversion — MyError("text")
+	if tv, ok := pass.TypesInfo.Types[call.Fun]; ok && tv.IsType() {
+		if len(call.Args) == 1 && !IsNilExpr(pass.TypesInfo, call.Args[0]) {
+			scrapTyped(ctx, pass, call)
+		}
+		return
+	}
+
 	ref := resolveFuncRef(fn)
 	if ref == nil {
+		if e.isNilError(pass, call) {
+			ctx.Add(&cir.ExprNil{}, call.Pos(), call.End())
+		}
 		return
 	}
 
-	span := ContextSpan{
-		start: call.Pos(),
-		end:   call.End(),
-	}
+	pos := pass.Fset.PositionFor(call.Pos(), false)
+
+	// Configuration entries are looked up with the spec reference, which is the one of
+	// an interface method for implementations of interfaces having entries.
+	spec := e.specRef(pass.Pkg, fn, *ref)
 
 	// error not last in returns
 	if sig := fn.Sig; sig != nil {
@@ -145,13 +235,14 @@ func (e *ScrapEngine) scrapCall(
 					res.At(i).Type(),
 					types.Universe.Lookup("error").Type(),
 				) {
-					e.r.Report(cerrules.ErrorMustBeLastReturnValue(), "", span.start)
+					e.r.Report(cerrules.ErrorMustBeLastReturnValue(), "", pos)
 					ctx.Add(
 						&cir.ExprCall{
 							HasArgs: len(call.Args) > 0,
 							Ref:     ref.CIR(),
 						},
-						span,
+						call.Pos(),
+						call.End(),
 					)
 					break
 				}
@@ -160,74 +251,193 @@ func (e *ScrapEngine) scrapCall(
 	}
 
 	// wrap
-	if ws, ok := e.wraps[*ref]; ok {
-		var src string
+	if ws, ok := e.wraps[spec]; ok {
+		var srcs []ast.Expr
 		var msg string
+		var msgArg ast.Expr
+		var opaque bool
 
 		switch ws.Kind {
 		case WrapKindFmt:
+			if len(call.Args) == 0 {
+				// Misconfigured wrapper, treat it as an ordinary call.
+				ctx.Add(
+					&cir.ExprCall{
+						Ref: ref.CIR(),
+					},
+					call.Pos(),
+					call.End(),
+				)
+				return
+			}
+
 			var isFmtNew bool
-			src, msg, isFmtNew = e.scrapFmtDetails(pass, call, span)
+			srcs, msg, opaque, isFmtNew = e.scrapFmtDetails(pass, call, pos)
+			msgArg = call.Args[0]
 			if isFmtNew {
+				e.checkMessage(pass, call.Args[0], literalArg(call.Args, 0), len(call.Args) == 1, pos)
 				ctx.Add(
 					&cir.ExprNew{
+						Msg: literalArg(call.Args, 0),
 						Ref: ref.CIR(),
 					},
-					span,
+					call.Pos(),
+					call.End(),
 				)
 				return
 			}
 
 		case WrapKindErrors:
-			if v, ok := call.Args[0].(*ast.Ident); ok {
-				src = v.Name
-			} else {
-				e.r.Report(cerrules.FixBeforeUse(), "", span.start)
+			if len(call.Args) < 2 {
+				// Misconfigured wrapper, treat it as an ordinary call.
+				ctx.Add(
+					&cir.ExprCall{
+						HasArgs: len(call.Args) > 0,
+						Ref:     ref.CIR(),
+					},
+					call.Pos(),
+					call.End(),
+				)
+				return
 			}
 
-			msgLit := extractStringLit(call.Args[1])
-			if msgLit != nil {
-				msg, _ = strconv.Unquote(msgLit.Value)
+			srcs = call.Args[:1]
+			msgArg = call.Args[1]
+			if msgLit := extractStringLit(call.Args[1]); msgLit != nil {
+				if v, err := strconv.Unquote(msgLit.Value); err == nil {
+					msg = v
+				}
+			}
+
+		case WrapKindJoin:
+			if call.Ellipsis.IsValid() {
+				// Errors joined are not known: errors.Join(errs...)
+				ctx.Add(
+					&cir.ExprCall{
+						HasArgs: true,
+						Ref:     ref.CIR(),
+					},
+					call.Pos(),
+					call.End(),
+				)
+				return
+			}
+
+			for _, arg := range call.Args {
+				if !IsNilExpr(pass.TypesInfo, arg) && ImplementsError(pass.TypesInfo.TypeOf(arg)) {
+					srcs = append(srcs, arg)
+				}
+			}
+			if len(srcs) == 0 {
+				// Nothing to join, the result is nil.
+				ctx.Add(&cir.ExprNil{}, call.Pos(), call.End())
+				return
 			}
 
 		default:
-			panic(fmt.Errorf("missing handling for wrap kind %s", ws.Kind))
+			panic(fmt.Errorf("missing handling for wrap kind %s", ws.Kind.String()))
 		}
-		ctx.Add(
-			&cir.ExprWrap{
-				Var: &cir.ExprVar{
-					Name: src,
+
+		for _, src := range srcs {
+			if _, ok := src.(*ast.Ident); !ok {
+				e.r.Report(cerrules.FixBeforeUse(), "", pos)
+				break
+			}
+		}
+
+		e.checkMessage(pass, msgArg, msg, false, pos)
+		if msg != "" {
+			e.annotations = append(e.annotations, Annotation{
+				Package: pass.Pkg.Path(),
+				Message: msg,
+				Pos:     pos,
+				at:      call.Pos(),
+			})
+		}
+
+		if len(srcs) == 1 && ws.Kind != WrapKindJoin {
+			ctx.Add(
+				&cir.ExprWrap{
+					Var:    wrappedVar(srcs[0]),
+					Msg:    msg,
+					Ref:    ref.CIR(),
+					Opaque: opaque,
 				},
-				Msg: msg,
-				Ref: ref.CIR(),
-			},
-			span,
-		)
+				call.Pos(),
+				call.End(),
+			)
+			return
+		}
+
+		join := &cir.ExprJoin{
+			Msg:    msg,
+			Ref:    ref.CIR(),
+			Opaque: opaque,
+		}
+		for _, src := range srcs {
+			join.Vars = append(join.Vars, wrappedVar(src))
+		}
+		ctx.Add(join, call.Pos(), call.End())
 		return
 	}
 
 	// logger
-	if ls, ok := e.loggers[*ref]; ok {
-		// TODO EXTRACT_LOGGING_COMPONENTS
+	if ls, ok := e.loggers[spec]; ok {
 		ctx.Add(
-			&cir.Log{
-				Var:   nil,
-				Level: 0,
-				Msg:   "",
-				Ref:   ls.Ref.CIR(),
-			},
-			span,
+			scrapLog(pass.TypesInfo, call, ls),
+			call.Pos(),
+			call.End(),
 		)
 		return
 	}
 
+	// transparent — the same error passed through
+	if _, ok := e.transparent[spec]; ok {
+		for _, arg := range call.Args {
+			if !IsError(pass.TypesInfo.TypeOf(arg)) {
+				continue
+			}
+
+			if id, ok := arg.(*ast.Ident); ok {
+				ctx.Add(
+					&cir.ExprAlias{
+						Target: id.Name,
+					},
+					call.Pos(),
+					call.End(),
+				)
+				return
+			}
+
+			e.r.Report(cerrules.FixBeforeUse(), "", pos)
+			break
+		}
+	}
+
+	// check — errors.Is, errors.As and alike
+	if node := scrapCheckCall(pass.TypesInfo, ref, call); node != nil {
+		ctx.Add(node, call.Pos(), call.End())
+		return
+	}
+
+	// nil error — an error result is documented to be always nil
+	if e.isNilError(pass, call) {
+		ctx.Add(&cir.ExprNil{}, call.Pos(), call.End())
+		return
+	}
+
 	// new (constructor) — with fmt-style “is actually wrap” discrimination
-	if ns, ok := e.news[*ref]; ok {
+	if ns, ok := e.news[spec]; ok {
+		if len(call.Args) > 0 {
+			e.checkMessage(pass, call.Args[0], literalArg(call.Args, 0), len(call.Args) == 1, pos)
+		}
 		ctx.Add(
 			&cir.ExprNew{
+				Msg: literalArg(call.Args, 0),
 				Ref: ns.Ref.CIR(),
 			},
-			span,
+			call.Pos(),
+			call.End(),
 		)
 		return
 	}
@@ -238,18 +448,141 @@ func (e *ScrapEngine) scrapCall(
 			HasArgs: len(call.Args) > 0,
 			Ref:     ref.CIR(),
 		},
-		span,
+		call.Pos(),
+		call.End(),
 	)
 }
 
+// scrapTyped records values of concrete types implementing error: composite literals,
+// their addresses and conversions.
+//
+//	&fs.PathError{…}
+//	myError("text")
+func scrapTyped(ctx *Context, pass *analysis.Pass, expr ast.Expr) {
+	t := pass.TypesInfo.TypeOf(expr)
+	if t == nil || types.IsInterface(t) || !ImplementsError(t) {
+		return
+	}
+
+	ref, ok := TypeRef(t)
+	if !ok {
+		return
+	}
+
+	ctx.Add(&cir.ExprType{Ref: ref}, expr.Pos(), expr.End())
+}
+
 func (e *ScrapEngine) scrapAssign(
 	ctx *Context,
 	pass *analysis.Pass,
 	as *ast.AssignStmt,
 ) {
-	// Here we can:
-	// - detect logging patterns
-	// - detect ignored errors in multi-value returns
+	// Look for errors discarded with "_":
+	//   data, _ := os.ReadFile(path)
+	//   _ = os.Remove(path)
+	//   a, _ := x, f()
+	var calls []*ast.CallExpr
+	var dsts []ast.Expr
+	switch {
+	case len(as.Rhs) == 1 && len(as.Lhs) > 1:
+		if call, ok := ast.Unparen(as.Rhs[0]).(*ast.CallExpr); ok {
+			calls = append(calls, call)
+			dsts = append(dsts, as.Lhs[len(as.Lhs)-1])
+		}
+	case len(as.Rhs) == len(as.Lhs):
+		for i, rhs := range as.Rhs {
+			if call, ok := ast.Unparen(rhs).(*ast.CallExpr); ok {
+				calls = append(calls, call)
+				dsts = append(dsts, as.Lhs[i])
+			}
+		}
+	}
+
+	for i, call := range calls {
+		if id, ok := dsts[i].(*ast.Ident); !ok || id.Name != "_" {
+			continue
+		}
+		if !returnsError(pass.TypesInfo, call) {
+			continue
+		}
+
+		dst := &cir.ExprVarHidden{}
+		dst.SetSpan(dsts[i].Pos(), dsts[i].End())
+		src := &cir.ExprCall{
+			HasArgs: len(call.Args) > 0,
+			Ref:     FuncRef(pass.TypesInfo, call),
+		}
+		src.SetSpan(call.Pos(), call.End())
+		ctx.Add(&cir.Assign{Dst: dst, Src: src}, dsts[i].Pos(), dsts[i].End())
+
+		if e.isNilError(pass, call) {
+			continue
+		}
+
+		e.r.Report(
+			cerrules.NoSilentDrop(),
+			fmt.Sprintf("error of %s is discarded", types.ExprString(call.Fun)),
+			pass.Fset.PositionFor(dsts[i].Pos(), false),
+		)
+	}
+}
+
+// scrapExprStmt looks for calls whose error results are dropped entirely:
+//
+//	os.Remove(path)
+func (e *ScrapEngine) scrapExprStmt(
+	pass *analysis.Pass,
+	stmt *ast.ExprStmt,
+) {
+	call, ok := ast.Unparen(stmt.X).(*ast.CallExpr)
+	if !ok || !returnsError(pass.TypesInfo, call) || e.isNilError(pass, call) {
+		return
+	}
+
+	e.r.Report(
+		cerrules.NoSilentDrop(),
+		fmt.Sprintf("error of %s is not used", types.ExprString(call.Fun)),
+		pass.Fset.PositionFor(call.Pos(), false),
+	)
+}
+
+// isNilError checks if the call is registered to return errors that can be dropped. Method
+// references are looked up both for the type that declares the method and for the type
+// of the receiver expression, so methods promoted from embedded types and interfaces
+// can be registered for the types embedding them:
+//
+//	var h hash.Hash
+//	h.Write(data) // hash.Hash.Write, though Write is declared in io.Writer
+//
+// Implementations of registered interface methods are matched too.
+func (e *ScrapEngine) isNilError(pass *analysis.Pass, call *ast.CallExpr) bool {
+	info := pass.TypesInfo
+	fn := callFn(info, call)
+	if ref := resolveFuncRef(fn); ref != nil {
+		if _, ok := e.nilErrors[e.specRef(pass.Pkg, fn, *ref)]; ok {
+			return true
+		}
+	}
+
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	selection, ok := info.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal {
+		return false
+	}
+	named, ok := types.Unalias(deref(selection.Recv())).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return false
+	}
+
+	_, ok = e.nilErrors[Reference{
+		Package: named.Obj().Pkg().Path(),
+		Type:    named.Obj().Name(),
+		Name:    selection.Obj().Name(),
+	}]
+	return ok
 }
 
 func (e *ScrapEngine) scrapReturn(
@@ -267,14 +600,9 @@ func (e *ScrapEngine) scrapIf(
 	pass *analysis.Pass,
 	stmt *ast.IfStmt,
 ) {
-	// Будем анализировать:
-	// - err != nil
-	// - err == nil
-	// - вызовы логгеров / wrap внутри веток
-	// - объявления err в init:  if err := f(); err != nil { ... }
-	// - branching-on-errors (для трассера: CER0XX)
-
-	// Пока просто оставляем точку входа
+	// Init statements, like in "if err := f(); err != nil", are ordinary
+	// statements walked on their own.
+	e.scrapCond(ctx, pass, stmt.Cond)
 }
 
 func (e *ScrapEngine) scrapSwitch(
@@ -282,10 +610,21 @@ func (e *ScrapEngine) scrapSwitch(
 	pass *analysis.Pass,
 	stmt *ast.SwitchStmt,
 ) {
-	// Интересует:
-	// - switch err { case ... }       → branching over error
-	// - switch x.(type)               → неактуально для ошибок, но может быть логгер
-	// - presence of logger/wrap/new inside cases
+	for _, s := range stmt.Body.List {
+		clause := s.(*ast.CaseClause)
+		for _, expr := range clause.List {
+			// switch { case err != nil: … }
+			if stmt.Tag == nil {
+				e.scrapCond(ctx, pass, expr)
+				continue
+			}
+
+			// switch err { case io.EOF: … }
+			if node := errorComparison(pass.TypesInfo, stmt.Tag, expr, true); node != nil {
+				ctx.Add(node, expr.Pos(), expr.End())
+			}
+		}
+	}
 }
 
 func (e *ScrapEngine) scrapTypeSwitch(
@@ -293,85 +632,261 @@ func (e *ScrapEngine) scrapTypeSwitch(
 	pass *analysis.Pass,
 	stmt *ast.TypeSwitchStmt,
 ) {
-	// Интерес:
-	// - switch err.(type)             → прототип type-based dispatch
-	//   (в cerrful будет относиться к CER<typename>-ветвлениям)
+	// switch err.(type) { … }
+	// switch e := err.(type) { … }
+	var x ast.Expr
+	switch v := stmt.Assign.(type) {
+	case *ast.ExprStmt:
+		x = v.X
+	case *ast.AssignStmt:
+		x = v.Rhs[0]
+	}
+	assert, ok := ast.Unparen(x).(*ast.TypeAssertExpr)
+	if !ok {
+		return
+	}
+	src := errorVar(pass.TypesInfo, assert.X)
+	if src == nil {
+		return
+	}
 
-	// Пока пусто
-}
+	for _, s := range stmt.Body.List {
+		clause := s.(*ast.CaseClause)
+		for _, expr := range clause.List {
+			if IsNilExpr(pass.TypesInfo, expr) {
+				ctx.Add(&cir.ErrorValueIsNil{Src: src}, expr.Pos(), expr.End())
+				continue
+			}
 
-var dummyWrapFormatLit = &ast.BasicLit{
-	Value: strconv.Quote(": %w"),
+			ref, ok := TypeRef(pass.TypesInfo.TypeOf(expr))
+			if !ok {
+				continue
+			}
+			ctx.Add(
+				&cir.ErrorTypeIsCheck{
+					Src:  src,
+					Type: ref,
+				},
+				expr.Pos(),
+				expr.End(),
+			)
+		}
+	}
 }
 
+// scrapFmtDetails returns errors wrapped with fmt-style wrapper and the message. Formats
+// must end with ": %w", every other %w verb must follow ": " as well:
+//
+//	fmt.Errorf("read %s: %w", name, err)           // msg: "read %s"
+//	fmt.Errorf("read: %w, close: %w", err1, err2)  // msg: "read, close"
+//
+// Errors wrapped are those of %w verbs. If there are none, errors formatted with other verbs
+// are wrapped opaquely, see [ScrapEngine.reportOpaque]. The first error is used otherwise.
 func (e *ScrapEngine) scrapFmtDetails(
 	pass *analysis.Pass,
 	call *ast.CallExpr,
-	span ContextSpan,
+	pos token.Position,
 ) (
-	src string,
+	srcs []ast.Expr,
 	msg string,
+	opaque bool,
 	isFmtNew bool,
 ) {
 	// Single-arg fmt.Errorf("msg") — это не wrap
 	if len(call.Args) == 1 {
-		return "", "", true
+		return nil, "", false, true
 	}
 
 	// --- FIND ERROR ARGUMENT ---
-	var errDetected bool
-	var errIndex int
-	for i, expr := range call.Args[1:] {
-		if types.Identical(
-			pass.TypesInfo.TypeOf(expr),
-			types.Universe.Lookup("error").Type(),
-		) {
-			errIndex = i
-			errDetected = true
-			break
+	args := call.Args[1:]
+	errIndex := slices.IndexFunc(args, func(expr ast.Expr) bool {
+		return IsError(pass.TypesInfo.TypeOf(expr))
+	})
+
+	lit, isLit := call.Args[0].(*ast.BasicLit)
+	var format string
+	var verbs []formatVerb
+	if isLit {
+		if v, err := strconv.Unquote(lit.Value); err == nil {
+			format = v
+			verbs = formatVerbs(format)
 		}
 	}
-	if !errDetected {
-		// fmt.Errorf("msg", x, y) без error → это fmt-new
-		return "", "", true
+
+	for _, verb := range verbs {
+		if verb.verb == 'w' && verb.arg < len(args) {
+			srcs = append(srcs, args[verb.arg])
+		}
+	}
+	var opaqueVerbs []formatVerb
+	if len(srcs) == 0 {
+		srcs, opaqueVerbs = opaqueErrors(pass.TypesInfo, args, verbs)
+	}
+	if len(srcs) == 0 {
+		if errIndex < 0 {
+			// fmt.Errorf("msg", x, y) без error → это fmt-new
+			return nil, "", false, true
+		}
+		srcs = args[errIndex : errIndex+1]
 	}
 
 	// --- FORMAT CHECK ---
-	v, ok := call.Args[0].(*ast.BasicLit)
-	if !ok {
-		// ставим dummy, чтобы unquote не умер
-		v = dummyWrapFormatLit
-		e.r.Report(cerrules.AnnotationFormatMustBeLiteral(), "", span.start)
+	const wrapSuffix = ": %w"
+	if !isLit {
+		e.r.Report(cerrules.AnnotationFormatMustBeLiteral(), "", pos)
+		format = wrapSuffix // dummy текст, чтобы parsing не умер
+	}
+	if len(opaqueVerbs) > 0 {
+		opaque = true
+		format = e.reportOpaque(lit, format, opaqueVerbs, args, srcs, pass.Fset, pos)
 	}
 
-	unquote, _ := strconv.Unquote(v.Value)
-	const wrapSuffix = ": %w"
-	if !strings.HasSuffix(unquote, wrapSuffix) {
-		e.r.Report(cerrules.AnnotationFormatMustEndWithW(), "", span.start)
-		unquote = wrapSuffix // dummy текст, чтобы parsing не умер
+	if !strings.HasSuffix(format, wrapSuffix) || strings.Count(format, "%w") != strings.Count(format, wrapSuffix) {
+		e.r.Report(cerrules.AnnotationFormatMustEndWithW(), "", pos)
+		format = wrapSuffix
 	}
-	msg = unquote[:len(unquote)-len(wrapSuffix)]
+	msg = strings.ReplaceAll(format, wrapSuffix, "")
 
-	// --- VARIABLE CHECK ---
-	variable := call.Args[errIndex+1]
-	if id, ok := variable.(*ast.Ident); ok {
-		src = id.Name
+	return srcs, msg, opaque, false
+}
+
+// opaqueErrors returns errors formatted with %v, %s or %q, directly or with their Error
+// methods, along with verbs used for them:
+//
+//	fmt.Errorf("read: %v", err)          // err
+//	fmt.Errorf("read: %s", err.Error())  // err
+func opaqueErrors(info *types.Info, args []ast.Expr, verbs []formatVerb) (srcs []ast.Expr, used []formatVerb) {
+	for _, verb := range verbs {
+		if !strings.ContainsRune("vsq", verb.verb) || verb.arg >= len(args) {
+			continue
+		}
+
+		arg := args[verb.arg]
+		if recv := errorMethodReceiver(info, arg); recv != nil {
+			srcs = append(srcs, recv)
+		} else if !IsNilExpr(info, arg) && ImplementsError(info.TypeOf(arg)) {
+			srcs = append(srcs, arg)
+		} else {
+			continue
+		}
+		used = append(used, verb)
+	}
+
+	return srcs, used
+}
+
+// errorMethodReceiver returns the error of the Error method call like err.Error().
+// Returns nil for other expressions.
+func errorMethodReceiver(info *types.Info, expr ast.Expr) ast.Expr {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok || len(call.Args) > 0 {
+		return nil
+	}
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Error" || !ImplementsError(info.TypeOf(sel.X)) {
+		return nil
+	}
+
+	return sel.X
+}
+
+// reportOpaque reports errors formatted opaquely and suggests to format them with %w
+// instead. Returns the format with %w verbs in place of the opaque ones.
+func (e *ScrapEngine) reportOpaque(
+	lit *ast.BasicLit,
+	format string,
+	verbs []formatVerb,
+	args []ast.Expr,
+	srcs []ast.Expr,
+	fset *token.FileSet,
+	pos token.Position,
+) string {
+	var descs []string
+	edits := make([]ReportEdit, 0, len(verbs)+1)
+	for i := len(verbs) - 1; i >= 0; i-- {
+		verb := verbs[i]
+		name := types.ExprString(srcs[i])
+		how := format[verb.start:verb.end]
+
+		arg := args[verb.arg]
+		if arg != srcs[i] {
+			// err.Error() → err
+			how = types.ExprString(arg)
+			edits = append(edits, ReportEdit{
+				Pos:     fset.PositionFor(arg.Pos(), false),
+				End:     fset.PositionFor(arg.End(), false),
+				NewText: name,
+			})
+		}
+
+		format = format[:verb.start] + "%w" + format[verb.end:]
+		descs = append(descs, fmt.Sprintf("error %s is formatted with %s", name, how))
+	}
+	slices.Reverse(descs)
+
+	var text string
+	if strings.HasPrefix(lit.Value, "`") && !strings.Contains(format, "`") {
+		text = "`" + format + "`"
 	} else {
-		e.r.Report(cerrules.FixBeforeUse(), "", span.start)
+		text = strconv.Quote(format)
 	}
+	edits = append(edits, ReportEdit{
+		Pos:     fset.PositionFor(lit.Pos(), false),
+		End:     fset.PositionFor(lit.End(), false),
+		NewText: text,
+	})
 
-	return src, msg, false
+	e.r.ReportWithFix(
+		cerrules.NoOpaqueErrorFormat(),
+		strings.Join(descs, ", ")+", errors.Is and errors.As cannot see through the annotation, use %w instead",
+		pos,
+		ReportFix{
+			Message: "Format errors with %w",
+			Edits:   edits,
+		},
+	)
+
+	return format
 }
 
 type Fn struct {
 	Name string
 	Sig  *types.Signature
-	Obj  *types.Func // может быть nil для интерфейсных методов
+	Obj  *types.Func
+}
+
+// callFn resolves the callee of the given call. Returns nil for calls of
+// builtins, type conversions and function values.
+func callFn(info *types.Info, call *ast.CallExpr) *Fn {
+	obj, ok := typeutil.Callee(info, call).(*types.Func)
+	if !ok {
+		return nil
+	}
+
+	sig, ok := info.TypeOf(call.Fun).(*types.Signature)
+	if !ok {
+		sig = obj.Type().(*types.Signature)
+	}
+
+	fn := &Fn{
+		Name: obj.Name(),
+		Sig:  sig,
+		Obj:  obj,
+	}
+
+	return fn
 }
 
+// resolveFuncRef returns a reference to the function. Methods are referred with types
+// declaring them, interface methods included:
+//
+//	var c io.ReadCloser
+//	c.Close() // "io".Closer.Close
+//
+// Returns nil for methods of anonymous interfaces.
 func resolveFuncRef(fn *Fn) *Reference {
 	if fn == nil || fn.Obj == nil {
-		return nil // интерфейсные методы не имеют референции
+		return nil
 	}
 
 	obj := fn.Obj
@@ -387,14 +902,60 @@ func resolveFuncRef(fn *Fn) *Reference {
 
 	// Если это метод → достаём тип-ресивер
 	if sig := obj.Type().(*types.Signature); sig.Recv() != nil {
-		if nt, ok := sig.Recv().Type().(*types.Named); ok {
-			ref.Type = nt.Obj().Name()
+		nt, ok := types.Unalias(deref(sig.Recv().Type())).(*types.Named)
+		if !ok {
+			return nil
 		}
+		ref.Type = nt.Obj().Name()
 	}
 
 	return ref
 }
 
+// returnsError checks if the last result of the call is an error.
+func returnsError(info *types.Info, call *ast.CallExpr) bool {
+	if tv, ok := info.Types[call.Fun]; !ok || tv.IsType() || tv.IsBuiltin() {
+		return false
+	}
+
+	switch t := info.TypeOf(call).(type) {
+	case *types.Tuple:
+		return t.Len() > 0 && IsError(t.At(t.Len()-1).Type())
+	default:
+		return IsError(t)
+	}
+}
+
+// FuncRef returns a reference to the function called. It is empty for calls of function values.
+func FuncRef(info *types.Info, call *ast.CallExpr) cir.Reference {
+	ref := resolveFuncRef(callFn(info, call))
+	if ref == nil {
+		return cir.Reference{}
+	}
+
+	return ref.CIR()
+}
+
+// IsError checks if the type is the error interface. Types are compared by identity
+// rather than with [types.Identical], as SSA has internal types the latter cannot handle.
+func IsError(t types.Type) bool {
+	return t != nil && types.Unalias(t) == types.Universe.Lookup("error").Type()
+}
+
+// ImplementsError checks if values of the type can be used as errors.
+func ImplementsError(t types.Type) bool {
+	errType := types.Universe.Lookup("error").Type()
+	return t != nil && types.Implements(t, errType.Underlying().(*types.Interface))
+}
+
+func deref(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+
+	return t
+}
+
 func extractStringLit(v ast.Expr) *ast.BasicLit {
 	switch vv := v.(type) {
 	case *ast.BasicLit:
@@ -0,0 +1,231 @@
+package tracing
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/sirkon/cerrful/internal/cir"
+)
+
+var (
+	errorsIs = Reference{Package: "errors", Name: "Is"}
+	errorsAs = Reference{Package: "errors", Name: "As"}
+)
+
+// osHelpers are standard library helpers checking errors for specific conditions.
+var osHelpers = map[Reference]bool{
+	{Package: "os", Name: "IsExist"}:      true,
+	{Package: "os", Name: "IsNotExist"}:   true,
+	{Package: "os", Name: "IsPermission"}: true,
+	{Package: "os", Name: "IsTimeout"}:    true,
+}
+
+// scrapCond records error checks of the condition. Logical operators are walked
+// through, so every comparison of "err != nil && !errors.Is(err, io.EOF)" is recorded
+// on its own. Calls, such as errors.Is, are recorded by [ScrapEngine.scrapCall].
+func (e *ScrapEngine) scrapCond(ctx *Context, pass *analysis.Pass, cond ast.Expr) {
+	switch v := ast.Unparen(cond).(type) {
+	case *ast.UnaryExpr:
+		if v.Op == token.NOT {
+			e.scrapCond(ctx, pass, v.X)
+		}
+
+	case *ast.BinaryExpr:
+		switch v.Op {
+		case token.LAND, token.LOR:
+			e.scrapCond(ctx, pass, v.X)
+			e.scrapCond(ctx, pass, v.Y)
+		case token.EQL, token.NEQ:
+			if node := errorComparison(pass.TypesInfo, v.X, v.Y, v.Op == token.EQL); node != nil {
+				ctx.Add(node, v.Pos(), v.End())
+			}
+		}
+	}
+}
+
+// errorComparison returns a CIR node for the comparison of errors. Returns nil if
+// neither of the operands is an error variable.
+func errorComparison(info *types.Info, x, y ast.Expr, equal bool) cir.Node {
+	src := errorVar(info, x)
+	if src == nil {
+		x, y = y, x
+		src = errorVar(info, x)
+	}
+	if src == nil {
+		return nil
+	}
+
+	if IsNilExpr(info, y) {
+		if equal {
+			return &cir.ErrorValueIsNil{Src: src}
+		}
+		return &cir.ErrorValueIsNotNil{Src: src}
+	}
+
+	rhs := errorValue(info, y)
+	if rhs == nil {
+		return nil
+	}
+	if equal {
+		return &cir.ErrorValueEQ{Src: src, RHS: rhs}
+	}
+	return &cir.ErrorValueNEQ{Src: src, RHS: rhs}
+}
+
+// scrapCheckCall returns a CIR node for calls checking errors: errors.Is, errors.As
+// and os helpers like os.IsNotExist. Returns nil for any other call.
+func scrapCheckCall(info *types.Info, ref *Reference, call *ast.CallExpr) cir.Node {
+	switch {
+	case *ref == errorsIs && len(call.Args) == 2:
+		sentinel, ok := errorValue(info, call.Args[1]).(*cir.ExprSentinel)
+		if !ok {
+			return nil
+		}
+
+		return &cir.ErrorTypeIsCheck{
+			Src:  errorVar(info, call.Args[0]),
+			Type: sentinel.Ref,
+			Ref:  ref.CIR(),
+		}
+
+	case *ref == errorsAs && len(call.Args) == 2:
+		target, ok := ast.Unparen(call.Args[1]).(*ast.UnaryExpr)
+		if !ok || target.Op != token.AND {
+			return nil
+		}
+
+		return &cir.ErrorTypeExtract{
+			Src:    errorVar(info, call.Args[0]),
+			Target: varRef(target.X),
+			Ref:    ref.CIR(),
+		}
+
+	case osHelpers[*ref] && len(call.Args) == 1:
+		return &cir.ErrorTypeIsHelperCheck{
+			Src: errorVar(info, call.Args[0]),
+			Ref: ref.CIR(),
+		}
+
+	default:
+		return nil
+	}
+}
+
+// errorVar returns a CIR variable if the expression is a local error variable.
+func errorVar(info *types.Info, expr ast.Expr) *cir.ExprVar {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok || !IsError(info.TypeOf(id)) {
+		return nil
+	}
+
+	obj, ok := info.Uses[id].(*types.Var)
+	if !ok || IsPackageLevel(obj) {
+		return nil
+	}
+
+	return exprVar(id)
+}
+
+// errorValue returns a CIR expression for an error value errors are compared with:
+// either a sentinel or a local error variable.
+func errorValue(info *types.Info, expr ast.Expr) cir.Expr {
+	if v := errorVar(info, expr); v != nil {
+		return v
+	}
+
+	obj := sentinelVar(info, expr)
+	if obj == nil {
+		return nil
+	}
+
+	res := &cir.ExprSentinel{
+		Ref: cir.Reference{
+			Package: obj.Pkg().Path(),
+			Name:    obj.Name(),
+		},
+	}
+	res.SetSpan(expr.Pos(), expr.End())
+	return res
+}
+
+// TypeRef returns a reference to the named type, pointers are dereferenced.
+func TypeRef(t types.Type) (cir.Reference, bool) {
+	named, ok := types.Unalias(deref(t)).(*types.Named)
+	if !ok || named.Obj().Pkg() == nil {
+		return cir.Reference{}, false
+	}
+
+	return cir.Reference{
+		Package: named.Obj().Pkg().Path(),
+		Name:    named.Obj().Name(),
+	}, true
+}
+
+func varRef(expr ast.Expr) *cir.ExprVar {
+	id, ok := ast.Unparen(expr).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+
+	return exprVar(id)
+}
+
+func exprVar(id *ast.Ident) *cir.ExprVar {
+	res := &cir.ExprVar{Name: id.Name}
+	res.SetSpan(id.Pos(), id.End())
+	return res
+}
+
+// wrappedVar returns a CIR variable of the wrapped error. Its name is empty if the
+// wrapped error is not a variable, such expressions must be fixed before use.
+func wrappedVar(expr ast.Expr) *cir.ExprVar {
+	if id, ok := expr.(*ast.Ident); ok {
+		return exprVar(id)
+	}
+
+	res := &cir.ExprVar{}
+	if expr != nil {
+		res.SetSpan(expr.Pos(), expr.End())
+	}
+	return res
+}
+
+// IsSentinelExpr checks if the expression refers to a package-level error variable.
+func IsSentinelExpr(info *types.Info, expr ast.Expr) bool {
+	return sentinelVar(info, expr) != nil
+}
+
+// sentinelVar returns the package-level error variable the expression refers to, nil
+// if there is none.
+func sentinelVar(info *types.Info, expr ast.Expr) *types.Var {
+	var id *ast.Ident
+	switch v := ast.Unparen(expr).(type) {
+	case *ast.Ident:
+		id = v
+	case *ast.SelectorExpr:
+		id = v.Sel
+	default:
+		return nil
+	}
+
+	obj, ok := info.Uses[id].(*types.Var)
+	if !ok || !IsPackageLevel(obj) || !IsError(obj.Type()) {
+		return nil
+	}
+
+	return obj
+}
+
+// IsNilExpr checks if the expression is the untyped nil.
+func IsNilExpr(info *types.Info, expr ast.Expr) bool {
+	tv, ok := info.Types[ast.Unparen(expr)]
+	return ok && tv.IsNil()
+}
+
+// IsPackageLevel checks if the object is declared at the package level.
+func IsPackageLevel(obj types.Object) bool {
+	return obj.Pkg() != nil && obj.Parent() == obj.Pkg().Scope()
+}
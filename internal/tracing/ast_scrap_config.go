@@ -11,13 +11,16 @@ import (
 	"github.com/sirkon/cerrful/internal/cir"
 )
 
-// WrapKind represents different wrap strategies (fmt-style, errors-style).
+// WrapKind represents different wrap strategies (fmt-style, errors-style, join-style).
 type WrapKind int
 
 const (
 	_ WrapKind = iota
 	WrapKindFmt
 	WrapKindErrors
+
+	// WrapKindJoin is for functions combining all their error arguments, like errors.Join.
+	WrapKindJoin
 )
 
 func (k *WrapKind) String() string {
@@ -39,6 +42,9 @@ func (k *WrapKind) UnmarshalText(b []byte) error {
 	case "errors":
 		*k = WrapKindErrors
 		return nil
+	case "join":
+		*k = WrapKindJoin
+		return nil
 	default:
 		return fmt.Errorf("unknown kind %q of wrap", b)
 	}
@@ -50,12 +56,14 @@ func (k *WrapKind) MarshalText() ([]byte, error) {
 		return []byte("fmt"), nil
 	case WrapKindErrors:
 		return []byte("errors"), nil
+	case WrapKindJoin:
+		return []byte("join"), nil
 	default:
 		return nil, fmt.Errorf("cannot marshal invalid WrapKind(%d)", *k)
 	}
 }
 
-// LoggingKind represents the style of logging (format-like, zap-like).
+// LoggingKind represents the style of logging (format-like, zap-like, etc).
 type LoggingKind int
 
 const (
@@ -63,6 +71,7 @@ const (
 	LoggingKindFormat
 	LoggingKindZap
 	LoggingKindZeroLog
+	LoggingKindSlog
 )
 
 func (k *LoggingKind) String() string {
@@ -87,6 +96,9 @@ func (k *LoggingKind) UnmarshalText(b []byte) error {
 	case "zerolog":
 		*k = LoggingKindZeroLog
 		return nil
+	case "slog":
+		*k = LoggingKindSlog
+		return nil
 	default:
 		return fmt.Errorf("unknown kind %q of logger", b)
 	}
@@ -100,6 +112,8 @@ func (k *LoggingKind) MarshalText() ([]byte, error) {
 		return []byte("zap"), nil
 	case LoggingKindZeroLog:
 		return []byte("zerolog"), nil
+	case LoggingKindSlog:
+		return []byte("slog"), nil
 	default:
 		return nil, fmt.Errorf("cannot marshal invalid LoggingKind(%d)", *k)
 	}
@@ -131,23 +145,37 @@ func (r *Reference) UnmarshalText(b []byte) error {
 	// Expected forms:
 	//   "pkg/path".Name
 	//   "pkg/path".Type.Name
+	// and shorthands for packages whose last path element has no dots:
+	//   pkg/path.Name
+	//   pkg/path.Type.Name
+
+	var pkg, rest string
+	if strings.HasPrefix(s, `"`) {
+		// 1) split at the quoted package
+		end := strings.Index(s[1:], `"`)
+		if end < 0 {
+			return fmt.Errorf("unterminated quoted package in reference: %q", s)
+		}
+		end++ // include the first quote
+
+		pkg = s[1:end]
+		rest = strings.TrimPrefix(s[end+1:], ".")
+	} else {
+		// 1) split at the first dot after the last slash
+		slash := strings.LastIndex(s, "/")
+		dot := strings.Index(s[slash+1:], ".")
+		if dot < 0 {
+			return fmt.Errorf("reference must contain a name: %q", s)
+		}
+		dot += slash + 1
 
-	// 1) split at the quoted package
-	if !strings.HasPrefix(s, `"`) {
-		return fmt.Errorf("reference must start with quoted package: %q", s)
-	}
-	end := strings.Index(s[1:], `"`)
-	if end < 0 {
-		return fmt.Errorf("unterminated quoted package in reference: %q", s)
+		pkg = s[:dot]
+		rest = s[dot+1:]
 	}
-	end++ // include the first quote
 
-	pkg := s[1:end]
 	if pkg == "" {
 		return fmt.Errorf("package cannot be empty in reference: %q", s)
 	}
-
-	rest := strings.TrimPrefix(s[end+1:], ".")
 	if rest == "" {
 		return fmt.Errorf("reference must contain a name: %q", s)
 	}
@@ -235,6 +263,18 @@ type NewSpec struct {
 	Ref Reference
 }
 
+// TransparentSpec describes a registered function that returns its error argument
+// as is, from the error-handling standpoint at least.
+type TransparentSpec struct {
+	Ref Reference
+}
+
+// NilErrorSpec describes a registered function whose error is documented to be always nil,
+// so it can be dropped silently.
+type NilErrorSpec struct {
+	Ref Reference
+}
+
 // IgnoredError marks an error type that should be treated as non-error
 // during analysis. These represent values such as io.EOF or context.Canceled
 // in circumstances where they do not indicate an actual failure.
@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// formatVerb is a verb of a fmt-style format consuming an argument.
+type formatVerb struct {
+	// verb is the verb character, like 'w' for %w.
+	verb rune
+
+	// arg is the index of the argument formatted, the format itself is not counted.
+	arg int
+
+	// start and end are offsets of the verb in the format.
+	start int
+	end   int
+}
+
+// formatVerbs returns verbs of the fmt-style format consuming arguments. Explicit argument
+// indexes and asterisks of widths and precisions are taken into account:
+//
+//	"read %s: %w"      // 's' of the argument 0, 'w' of the argument 1
+//	"%[2]w: %[1]s"     // 'w' of the argument 1, 's' of the argument 0
+//	"%*d: %w"          // 'd' of the argument 1, 'w' of the argument 2
+func formatVerbs(format string) []formatVerb {
+	var res []formatVerb
+	var arg int
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		start := i
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0", format[i]) >= 0 {
+			i++
+		}
+
+	modifiers:
+		for i < len(format) {
+			switch c := format[i]; {
+			case c == '[':
+				end := strings.IndexByte(format[i:], ']')
+				if end < 0 {
+					return res
+				}
+				if n, err := strconv.Atoi(format[i+1 : i+end]); err == nil && n > 0 {
+					arg = n - 1
+				}
+				i += end + 1
+			case c == '*':
+				arg++
+				i++
+			case c == '.' || '0' <= c && c <= '9':
+				i++
+			default:
+				break modifiers
+			}
+		}
+		if i >= len(format) {
+			return res
+		}
+
+		verb, size := utf8.DecodeRuneInString(format[i:])
+		i += size - 1
+		if verb == '%' {
+			continue
+		}
+
+		res = append(res, formatVerb{
+			verb:  verb,
+			arg:   arg,
+			start: start,
+			end:   i + 1,
+		})
+		arg++
+	}
+
+	return res
+}
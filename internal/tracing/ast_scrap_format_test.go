@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFormatVerbs(t *testing.T) {
+	tests := []struct {
+		format string
+		want   []formatVerb
+	}{
+		{
+			format: "read %s: %w",
+			want: []formatVerb{
+				{verb: 's', arg: 0, start: 5, end: 7},
+				{verb: 'w', arg: 1, start: 9, end: 11},
+			},
+		},
+		{
+			format: "100%% of %[2]w: %[1]q",
+			want: []formatVerb{
+				{verb: 'w', arg: 1, start: 9, end: 14},
+				{verb: 'q', arg: 0, start: 16, end: 21},
+			},
+		},
+		{
+			format: "%-*.2f: %+v",
+			want: []formatVerb{
+				{verb: 'f', arg: 1, start: 0, end: 6},
+				{verb: 'v', arg: 2, start: 8, end: 11},
+			},
+		},
+		{
+			format: "no verbs, %",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			if got := formatVerbs(tt.format); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unexpected verbs\n got: %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package tracing
+
+import (
+	"go/types"
+	"slices"
+	"strings"
+)
+
+// specRef returns the reference configuration entries of the method are looked up with.
+// Methods having no entries of their own are matched against entries of interface methods
+// their receivers implement:
+//
+//	var f *os.File
+//	f.Close() // "io".Closer.Close, if only it is registered
+//
+// Interfaces are looked up in the package and its dependencies. The first interface
+// implemented is taken if there are several of them, in the order of references.
+func (e *ScrapEngine) specRef(pkg *types.Package, fn *Fn, ref Reference) Reference {
+	if ref.Type == "" || fn == nil || fn.Obj == nil || e.registered(ref) {
+		return ref
+	}
+
+	recv := fn.Obj.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return ref
+	}
+
+	for _, method := range e.methods() {
+		if method.Name != ref.Name || method == ref {
+			continue
+		}
+
+		iface := e.iface(pkg, method)
+		if iface == nil {
+			continue
+		}
+
+		if implements(recv.Type(), iface) {
+			return method
+		}
+	}
+
+	return ref
+}
+
+// registered checks if there are configuration entries for the reference.
+func (e *ScrapEngine) registered(ref Reference) bool {
+	if _, ok := e.wraps[ref]; ok {
+		return true
+	}
+	if _, ok := e.loggers[ref]; ok {
+		return true
+	}
+	if _, ok := e.news[ref]; ok {
+		return true
+	}
+	if _, ok := e.transparent[ref]; ok {
+		return true
+	}
+	if _, ok := e.nilErrors[ref]; ok {
+		return true
+	}
+
+	return false
+}
+
+// methods returns sorted references of configuration entries for methods.
+func (e *ScrapEngine) methods() []Reference {
+	if e.methodRefs != nil {
+		return e.methodRefs
+	}
+
+	res := []Reference{}
+	add := func(ref Reference) {
+		if ref.Type != "" {
+			res = append(res, ref)
+		}
+	}
+
+	for ref := range e.wraps {
+		add(ref)
+	}
+	for ref := range e.loggers {
+		add(ref)
+	}
+	for ref := range e.news {
+		add(ref)
+	}
+	for ref := range e.transparent {
+		add(ref)
+	}
+	for ref := range e.nilErrors {
+		add(ref)
+	}
+
+	slices.SortFunc(res, func(a, b Reference) int {
+		if c := strings.Compare(a.Package, b.Package); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Type, b.Type); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	e.methodRefs = slices.Compact(res)
+	return e.methodRefs
+}
+
+// iface returns the interface declaring the method referred. Returns nil if the reference
+// is not of an interface method or the interface cannot be found.
+func (e *ScrapEngine) iface(pkg *types.Package, method Reference) *types.Interface {
+	key := Reference{Package: method.Package, Type: method.Type}
+	if res, ok := e.ifaces[key]; ok {
+		return res
+	}
+
+	var res *types.Interface
+	if p := lookupPackage(pkg, method.Package); p != nil {
+		if tn, ok := p.Scope().Lookup(method.Type).(*types.TypeName); ok {
+			res, _ = tn.Type().Underlying().(*types.Interface)
+		}
+	}
+	e.ifaces[key] = res
+
+	return res
+}
+
+// implements checks if the receiver type implements the interface. Value receivers of methods
+// can be called on pointers as well, so pointers to them are checked too.
+func implements(recv types.Type, iface *types.Interface) bool {
+	if types.Implements(recv, iface) {
+		return true
+	}
+
+	if _, ok := recv.(*types.Pointer); ok || types.IsInterface(recv) {
+		return false
+	}
+
+	return types.Implements(types.NewPointer(recv), iface)
+}
+
+// lookupPackage looks for the package with the given path among the package and its dependencies.
+func lookupPackage(pkg *types.Package, path string) *types.Package {
+	if pkg == nil {
+		return nil
+	}
+
+	seen := map[*types.Package]bool{}
+	queue := []*types.Package{pkg}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		if p.Path() == path {
+			return p
+		}
+		queue = append(queue, p.Imports()...)
+	}
+
+	return nil
+}
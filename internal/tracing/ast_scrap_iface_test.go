@@ -0,0 +1,133 @@
+package tracing
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/sirkon/cerrful/internal/cir"
+)
+
+func TestScrapInterfaceMethods(t *testing.T) {
+	const src = `package p
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+type factory interface {
+	New(msg string) error
+}
+
+type logger interface {
+	Error(msg string, args ...any)
+}
+
+type myFactory struct{}
+
+func (myFactory) New(msg string) error { return errors.New(msg) }
+
+func g(fc factory, l logger, f *os.File, c io.ReadCloser) {
+	err := fc.New("via interface")
+	err = myFactory{}.New("via implementation")
+	err = f.Close()
+	err = c.Close()
+	l.Error("close", "err", err)
+}
+`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+		Implicits:  map[ast.Node]types.Object{},
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("p", fset, []*ast.File{file}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRef := Reference{Package: "p", Type: "factory", Name: "New"}
+	logRef := Reference{Package: "p", Type: "logger", Name: "Error"}
+
+	var reports ReportEngine
+	engine := NewScrapEngine(reports.Phase(ReportScrap))
+	engine.RegisterNew(newRef)
+	engine.RegisterLogger(logRef, LoggingKindSlog)
+	engine.RegisterNilError(Reference{Package: "io", Type: "Closer", Name: "Close"})
+
+	ctx := NewContext()
+	engine.Scrap(ctx, &analysis.Pass{
+		Fset:      fset,
+		Files:     []*ast.File{file},
+		Pkg:       pkg,
+		TypesInfo: info,
+	}, file)
+
+	tests := []struct {
+		at   string
+		want cir.Node
+	}{
+		{
+			at:   "fc.New(",
+			want: &cir.ExprNew{Msg: "via interface", Ref: newRef.CIR()},
+		},
+		{
+			at:   "myFactory{}.New(",
+			want: &cir.ExprNew{Msg: "via implementation", Ref: newRef.CIR()},
+		},
+		{
+			at:   "f.Close(",
+			want: &cir.ExprNil{},
+		},
+		{
+			at:   "c.Close(",
+			want: &cir.ExprNil{},
+		},
+		{
+			at: "l.Error(",
+			want: &cir.Log{
+				Var:   &cir.ExprVar{Name: "err"},
+				Level: cir.LogLevelError,
+				Msg:   "close",
+				Ref:   logRef.CIR(),
+			},
+		},
+	}
+
+	tf := fset.File(file.Pos())
+	for _, tt := range tests {
+		t.Run(tt.at, func(t *testing.T) {
+			offset := strings.Index(src, tt.at)
+			if offset < 0 {
+				t.Fatalf("no %q in the source", tt.at)
+			}
+
+			got := ctx.GetByPos(tf.Pos(offset))
+			if got == nil {
+				t.Fatal("no node found")
+			}
+
+			if got = clearSpans(got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unexpected node\n got: %#v\nwant: %#v", got, tt.want)
+			}
+		})
+	}
+}
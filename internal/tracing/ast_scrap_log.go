@@ -0,0 +1,224 @@
+package tracing
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"strconv"
+	"strings"
+
+	"github.com/sirkon/cerrful/internal/cir"
+)
+
+const (
+	zapPackage     = "go.uber.org/zap"
+	zerologPackage = "github.com/rs/zerolog"
+	slogPackage    = "log/slog"
+)
+
+// scrapLog extracts the logged error, message and level of the logging call.
+// Each kind of logger keeps them in its own way:
+//
+//	log.Printf("do: %v", err)              // format: an error among arguments
+//	logger.Error("do", zap.Error(err))     // zap: zap.Error and zap.NamedError fields
+//	log.Error().Err(err).Msg("do")         // zerolog: Err in the chain ending with Msg
+//	slog.Error("do", slog.Any("err", err)) // slog: slog.Any attributes or key-value pairs
+func scrapLog(info *types.Info, call *ast.CallExpr, spec LoggerSpec) *cir.Log {
+	res := &cir.Log{
+		Level: logLevel(spec.Ref.Name),
+		Ref:   spec.Ref.CIR(),
+	}
+
+	switch spec.Kind {
+	case LoggingKindFormat:
+		res.Var = logVar(info, call.Args, nil)
+		res.Msg = literalArg(call.Args, 0)
+
+	case LoggingKindZap:
+		res.Var = logVar(info, call.Args[min(1, len(call.Args)):], func(fn *Reference, args []ast.Expr) ast.Expr {
+			switch {
+			case fn.Package != zapPackage || fn.Type != "":
+				return nil
+			case fn.Name == "Error" && len(args) == 1:
+				return args[0]
+			case fn.Name == "NamedError" && len(args) == 2:
+				return args[1]
+			default:
+				return nil
+			}
+		})
+		res.Msg = literalArg(call.Args, 0)
+
+	case LoggingKindZeroLog:
+		res.Var, res.Level = zerologChain(info, call)
+		res.Msg = literalArg(call.Args, 0)
+
+	case LoggingKindSlog:
+		// Message position depends on the method:
+		//   Error(msg, args...)
+		//   ErrorContext(ctx, msg, args...)
+		//   Log(ctx, level, msg, args...)
+		msg := 0
+		switch name := spec.Ref.Name; {
+		case name == "Log" || name == "LogAttrs":
+			msg = 2
+			if len(call.Args) > 1 {
+				res.Level = slogLevel(info, call.Args[1])
+			}
+		case strings.HasSuffix(name, "Context"):
+			msg = 1
+		}
+
+		res.Msg = literalArg(call.Args, msg)
+		res.Var = logVar(info, call.Args[min(msg+1, len(call.Args)):], func(fn *Reference, args []ast.Expr) ast.Expr {
+			if fn.Package == slogPackage && fn.Type == "" && fn.Name == "Any" && len(args) == 2 {
+				return args[1]
+			}
+			return nil
+		})
+	}
+
+	return res
+}
+
+// logVar looks for an error among arguments. The field function returns an error argument
+// of a call building a structured logging field or nil if it is not such a call.
+func logVar(
+	info *types.Info,
+	args []ast.Expr,
+	field func(fn *Reference, args []ast.Expr) ast.Expr,
+) cir.Expr {
+	for _, arg := range args {
+		if IsError(info.TypeOf(arg)) {
+			return logExpr(arg)
+		}
+
+		call, ok := ast.Unparen(arg).(*ast.CallExpr)
+		if !ok || field == nil {
+			continue
+		}
+		fn := resolveFuncRef(callFn(info, call))
+		if fn == nil {
+			continue
+		}
+		if v := field(fn, call.Args); v != nil && IsError(info.TypeOf(v)) {
+			return logExpr(v)
+		}
+	}
+
+	return nil
+}
+
+// logExpr represents the logged error. Only variables are represented yet.
+func logExpr(arg ast.Expr) cir.Expr {
+	id, ok := ast.Unparen(arg).(*ast.Ident)
+	if !ok {
+		return nil
+	}
+
+	return exprVar(id)
+}
+
+// literalArg returns the message at the given argument position, if it is a string literal.
+func literalArg(args []ast.Expr, i int) string {
+	if i >= len(args) {
+		return ""
+	}
+
+	lit, ok := ast.Unparen(args[i]).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+
+	msg, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+
+	return msg
+}
+
+// zerologChain walks the chain of event methods back from its final Msg/Msgf/Send call
+// to find the logged error and the level of the event:
+//
+//	log.Error().Str("path", path).Err(err).Msg("read file")
+//	logger.Err(err).Send()
+func zerologChain(info *types.Info, call *ast.CallExpr) (cir.Expr, cir.LogLevel) {
+	var res cir.Expr
+	level := cir.LogLevelWarn
+
+	for {
+		sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+		if !ok {
+			break
+		}
+		prev, ok := ast.Unparen(sel.X).(*ast.CallExpr)
+		if !ok {
+			break
+		}
+		call = prev
+
+		fn := resolveFuncRef(callFn(info, call))
+		if fn == nil || !strings.HasPrefix(fn.Package, zerologPackage) {
+			break
+		}
+
+		switch fn.Name {
+		case "Err":
+			// Event.Err, as well as Logger.Err and log.Err starting an event.
+			if len(call.Args) == 1 && res == nil {
+				res = logExpr(call.Args[0])
+			}
+			if fn.Type != "Event" {
+				level = cir.LogLevelError
+			}
+		case "AnErr":
+			if len(call.Args) == 2 && res == nil {
+				res = logExpr(call.Args[1])
+			}
+		default:
+			if fn.Type != "Event" {
+				level = logLevel(fn.Name)
+			}
+		}
+	}
+
+	return res, level
+}
+
+// slogLevel infers the log level from the level argument of slog Log and LogAttrs.
+func slogLevel(info *types.Info, arg ast.Expr) cir.LogLevel {
+	if tv, ok := info.Types[arg]; ok && tv.Value != nil {
+		// slog.Level values: Debug = -4, Info = 0, Warn = 4, Error = 8.
+		if v, ok := constant.Int64Val(constant.ToInt(tv.Value)); ok && v >= 8 {
+			return cir.LogLevelError
+		}
+		return cir.LogLevelWarn
+	}
+
+	var name string
+	switch v := ast.Unparen(arg).(type) {
+	case *ast.SelectorExpr:
+		name = v.Sel.Name
+	case *ast.Ident:
+		name = v.Name
+	}
+
+	return logLevel(strings.TrimPrefix(name, "Level"))
+}
+
+// logLevel infers the log level from the name of a logging method or function:
+// fatal and panic ones are fatal, error ones are errors and everything else is
+// considered to be a warning at most.
+func logLevel(name string) cir.LogLevel {
+	name = strings.ToLower(name)
+	switch {
+	case strings.Contains(name, "fatal"), strings.Contains(name, "panic"):
+		return cir.LogLevelFatal
+	case strings.HasPrefix(name, "err"):
+		return cir.LogLevelError
+	default:
+		return cir.LogLevelWarn
+	}
+}
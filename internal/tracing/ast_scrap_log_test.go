@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"testing"
+
+	"github.com/sirkon/cerrful/internal/cir"
+)
+
+func TestScrapLog(t *testing.T) {
+	const src = `package p
+
+import (
+	"context"
+	"log"
+	"log/slog"
+)
+
+func f(ctx context.Context, err error) {
+	log.Printf("do: %v", err)
+	log.Fatalln("do", err)
+	slog.Error("do", slog.Any("err", err))
+	slog.WarnContext(ctx, "do", "err", err)
+	slog.Log(ctx, slog.LevelError, "do", "err", err)
+	slog.Info("do")
+}
+`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("p", fset, []*ast.File{file}, info); err != nil {
+		t.Fatal(err)
+	}
+
+	format := func(name string) LoggerSpec {
+		return LoggerSpec{Ref: Reference{Package: "log", Name: name}, Kind: LoggingKindFormat}
+	}
+	slogSpec := func(name string) LoggerSpec {
+		return LoggerSpec{Ref: Reference{Package: "log/slog", Name: name}, Kind: LoggingKindSlog}
+	}
+	errVar := &cir.ExprVar{Name: "err"}
+
+	tests := []struct {
+		spec LoggerSpec
+		want *cir.Log
+	}{
+		{
+			spec: format("Printf"),
+			want: &cir.Log{Var: errVar, Level: cir.LogLevelWarn, Msg: "do: %v", Ref: cir.Reference{Package: "log", Name: "Printf"}},
+		},
+		{
+			spec: format("Fatalln"),
+			want: &cir.Log{Var: errVar, Level: cir.LogLevelFatal, Msg: "do", Ref: cir.Reference{Package: "log", Name: "Fatalln"}},
+		},
+		{
+			spec: slogSpec("Error"),
+			want: &cir.Log{Var: errVar, Level: cir.LogLevelError, Msg: "do", Ref: cir.Reference{Package: "log/slog", Name: "Error"}},
+		},
+		{
+			spec: slogSpec("WarnContext"),
+			want: &cir.Log{Var: errVar, Level: cir.LogLevelWarn, Msg: "do", Ref: cir.Reference{Package: "log/slog", Name: "WarnContext"}},
+		},
+		{
+			spec: slogSpec("Log"),
+			want: &cir.Log{Var: errVar, Level: cir.LogLevelError, Msg: "do", Ref: cir.Reference{Package: "log/slog", Name: "Log"}},
+		},
+		{
+			spec: slogSpec("Info"),
+			want: &cir.Log{Level: cir.LogLevelWarn, Msg: "do", Ref: cir.Reference{Package: "log/slog", Name: "Info"}},
+		},
+	}
+
+	var calls []*ast.CallExpr
+	for _, stmt := range file.Decls[1].(*ast.FuncDecl).Body.List {
+		calls = append(calls, stmt.(*ast.ExprStmt).X.(*ast.CallExpr))
+	}
+	if len(calls) != len(tests) {
+		t.Fatalf("expected %d calls, got %d", len(tests), len(calls))
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.spec.Ref.Name, func(t *testing.T) {
+			got := scrapLog(info, calls[i], tt.spec)
+			if v, ok := got.Var.(*cir.ExprVar); ok && !v.Pos().IsValid() {
+				t.Error("logged variable has no position")
+			}
+
+			if clearSpans(got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unexpected log\n got: %+v\nwant: %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package tracing
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/sirkon/cerrful/internal/cerrules"
+)
+
+// ForbiddenTerm is a term error messages must not contain.
+type ForbiddenTerm struct {
+	// Term is the word, the phrase or the regular expression as it is configured.
+	Term string
+
+	// Pattern matches the term in messages.
+	Pattern *regexp.Regexp
+
+	// Reason tells why the term is forbidden.
+	Reason string
+
+	// Replacement is the text suggested instead of the term, an empty one drops it.
+	// It is used literally. No fix is suggested if it is nil.
+	Replacement *string
+}
+
+// WordPattern returns the pattern matching the word or the phrase as a whole ignoring case.
+// Words of phrases can be separated with any whitespace.
+func WordPattern(word string) *regexp.Regexp {
+	words := strings.Fields(word)
+	for i, w := range words {
+		words[i] = regexp.QuoteMeta(w)
+	}
+
+	pattern := strings.Join(words, `\s+`)
+	if first, _ := utf8.DecodeRuneInString(word); isWordRune(first) {
+		pattern = `\b` + pattern
+	}
+	if last, _ := utf8.DecodeLastRuneInString(word); isWordRune(last) {
+		pattern += `\b`
+	}
+
+	return regexp.MustCompile(`(?i)` + pattern)
+}
+
+// DefaultForbiddenTerms returns built-in forbidden terms.
+func DefaultForbiddenTerms() []ForbiddenTerm {
+	drop := ""
+	term := func(word, reason string) ForbiddenTerm {
+		return ForbiddenTerm{
+			Term:        word,
+			Pattern:     WordPattern(word),
+			Reason:      reason,
+			Replacement: &drop,
+		}
+	}
+
+	return []ForbiddenTerm{
+		term("err", "the error is implied, abbreviations make messages harder to read"),
+		term("couldn't", "an error is a failure already"),
+		term("can't", "an error is a failure already"),
+	}
+}
+
+// SetForbiddenTerms sets terms error messages must not contain, they replace built-in ones.
+func (e *ScrapEngine) SetForbiddenTerms(terms []ForbiddenTerm) {
+	e.forbiddenTerms = terms
+}
+
+// checkForbiddenTerms reports forbidden terms found in the message. A fix replacing the term
+// is suggested if the message argument is a string literal and the term has a replacement:
+//
+//	fmt.Errorf("couldn't read file: %w", err) // → fmt.Errorf("read file: %w", err)
+func (e *ScrapEngine) checkForbiddenTerms(pass *analysis.Pass, arg ast.Expr, msg string, pos token.Position) {
+	for _, term := range e.forbiddenTerms {
+		loc := term.Pattern.FindStringIndex(msg)
+		if loc == nil {
+			continue
+		}
+
+		text := fmt.Sprintf("message %q contains forbidden term %q: %s", msg, msg[loc[0]:loc[1]], term.Reason)
+		e.reportTerm(cerrules.NoForbiddenTerms(), text, pos, pass, arg, msg, term)
+	}
+}
+
+// reportTerm reports the term found in the message along with the fix replacing it if there
+// is one, see [forbiddenTermFix].
+func (e *ScrapEngine) reportTerm(
+	rule cerrules.Rule,
+	text string,
+	pos token.Position,
+	pass *analysis.Pass,
+	arg ast.Expr,
+	msg string,
+	term ForbiddenTerm,
+) {
+	fix, ok := forbiddenTermFix(pass, arg, msg, term)
+	if !ok {
+		e.r.Report(rule, text, pos)
+		return
+	}
+
+	e.r.ReportWithFix(rule, text, pos, fix)
+}
+
+// forbiddenTermFix returns the fix replacing every occurrence of the term in the message
+// of the literal. Spaces around dropped terms are dropped as well. The message must start
+// the literal value, like the one of "read: %w" does. No fix is returned for literals having
+// escape sequences, offsets in their values differ from ones in the source.
+func forbiddenTermFix(pass *analysis.Pass, arg ast.Expr, msg string, term ForbiddenTerm) (ReportFix, bool) {
+	if term.Replacement == nil || arg == nil {
+		return ReportFix{}, false
+	}
+
+	lit, ok := ast.Unparen(arg).(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ReportFix{}, false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil || value != lit.Value[1:len(lit.Value)-1] || !strings.HasPrefix(value, msg) {
+		return ReportFix{}, false
+	}
+
+	var edits []ReportEdit
+	for _, loc := range term.Pattern.FindAllStringIndex(msg, -1) {
+		start, end := loc[0], loc[1]
+		if *term.Replacement == "" {
+			start, end = dropSpaces(msg, start, end)
+		}
+		if start == end {
+			continue
+		}
+
+		// The value starts right after the opening quote.
+		pos := lit.Pos() + 1
+		edits = append(edits, ReportEdit{
+			Pos:     pass.Fset.PositionFor(pos+token.Pos(start), false),
+			End:     pass.Fset.PositionFor(pos+token.Pos(end), false),
+			NewText: *term.Replacement,
+		})
+	}
+	if len(edits) == 0 {
+		return ReportFix{}, false
+	}
+
+	title := fmt.Sprintf("Replace %q with %q", term.Term, *term.Replacement)
+	if *term.Replacement == "" {
+		title = fmt.Sprintf("Drop %q", term.Term)
+	}
+
+	return ReportFix{
+		Message: title,
+		Edits:   edits,
+	}, true
+}
+
+// dropSpaces extends the range of the text dropped with spaces following it, or with ones
+// preceding it if there are none, so that words around are separated with a single space:
+//
+//	"failed to read" → "read"
+//	"read err: %w"   → "read: %w"
+func dropSpaces(text string, start, end int) (int, int) {
+	if n := len(text[end:]) - len(strings.TrimLeft(text[end:], " \t")); n > 0 {
+		return start, end + n
+	}
+
+	return len(strings.TrimRight(text[:start], " \t")), end
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
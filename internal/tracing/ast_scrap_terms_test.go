@@ -0,0 +1,50 @@
+package tracing
+
+import "testing"
+
+func TestWordPattern(t *testing.T) {
+	tests := []struct {
+		word  string
+		text  string
+		match bool
+	}{
+		{word: "err", text: "read err", match: true},
+		{word: "err", text: "read error"},
+		{word: "err", text: "ERR: read", match: true},
+		{word: "failed to", text: "read: failed\tto open", match: true},
+		{word: "failed to", text: "read: failed open"},
+		{word: "couldn't", text: "couldn't read", match: true},
+		{word: "a.b", text: "axb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.word+"/"+tt.text, func(t *testing.T) {
+			if got := WordPattern(tt.word).MatchString(tt.text); got != tt.match {
+				t.Errorf("%q matches %q: %t, want %t", tt.word, tt.text, got, tt.match)
+			}
+		})
+	}
+}
+
+func TestDropSpaces(t *testing.T) {
+	tests := []struct {
+		text string
+		term string
+		want string
+	}{
+		{text: "failed to read", term: "failed to", want: "read"},
+		{text: "read err: %w", term: "err", want: "read: %w"},
+		{text: "read  err", term: "err", want: "read"},
+		{text: "err", term: "err", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			loc := WordPattern(tt.term).FindStringIndex(tt.text)
+			start, end := dropSpaces(tt.text, loc[0], loc[1])
+			if got := tt.text[:start] + tt.text[end:]; got != tt.want {
+				t.Errorf("dropping %q from %q gives %q, want %q", tt.term, tt.text, got, tt.want)
+			}
+		})
+	}
+}
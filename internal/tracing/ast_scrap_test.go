@@ -0,0 +1,169 @@
+package tracing
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/sirkon/cerrful/internal/cir"
+)
+
+func TestScrapChecks(t *testing.T) {
+	const src = `package p
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+)
+
+func f() error { return nil }
+
+func g() {
+	if err := f(); err != nil {
+	}
+
+	err := f()
+	if err == nil || !errors.Is(err, io.EOF) {
+	}
+
+	switch err {
+	case io.EOF:
+	case nil:
+	}
+
+	switch e := err.(type) {
+	case *fs.PathError:
+		_ = e
+	}
+
+	var pe *fs.PathError
+	if errors.As(err, &pe) && os.IsNotExist(err) {
+	}
+}
+`
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info := &types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+		Implicits:  map[ast.Node]types.Object{},
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := conf.Check("p", fset, []*ast.File{file}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var reports ReportEngine
+	ctx := NewContext()
+	NewScrapEngine(reports.Phase(ReportScrap)).Scrap(ctx, &analysis.Pass{
+		Fset:      fset,
+		Files:     []*ast.File{file},
+		Pkg:       pkg,
+		TypesInfo: info,
+	}, file)
+
+	errVar := &cir.ExprVar{Name: "err"}
+	eof := cir.Reference{Package: "io", Name: "EOF"}
+	tests := []struct {
+		at   string
+		want cir.Node
+	}{
+		{
+			at:   "err != nil",
+			want: &cir.ErrorValueIsNotNil{Src: errVar},
+		},
+		{
+			at:   "err == nil",
+			want: &cir.ErrorValueIsNil{Src: errVar},
+		},
+		{
+			at: "errors.Is(",
+			want: &cir.ErrorTypeIsCheck{
+				Src:  errVar,
+				Type: eof,
+				Ref:  cir.Reference{Package: "errors", Name: "Is"},
+			},
+		},
+		{
+			at:   "io.EOF:",
+			want: &cir.ErrorValueEQ{Src: errVar, RHS: &cir.ExprSentinel{Ref: eof}},
+		},
+		{
+			at:   "nil:",
+			want: &cir.ErrorValueIsNil{Src: errVar},
+		},
+		{
+			at: "*fs.PathError:",
+			want: &cir.ErrorTypeIsCheck{
+				Src:  errVar,
+				Type: cir.Reference{Package: "io/fs", Name: "PathError"},
+			},
+		},
+		{
+			at: "errors.As(",
+			want: &cir.ErrorTypeExtract{
+				Src:    errVar,
+				Target: &cir.ExprVar{Name: "pe"},
+				Ref:    cir.Reference{Package: "errors", Name: "As"},
+			},
+		},
+		{
+			at: "os.IsNotExist(",
+			want: &cir.ErrorTypeIsHelperCheck{
+				Src: errVar,
+				Ref: cir.Reference{Package: "os", Name: "IsNotExist"},
+			},
+		},
+	}
+
+	tf := fset.File(file.Pos())
+	for _, tt := range tests {
+		t.Run(tt.at, func(t *testing.T) {
+			offset := strings.Index(src, tt.at)
+			if offset < 0 {
+				t.Fatalf("no %q in the source", tt.at)
+			}
+
+			pos := tf.Pos(offset)
+			got := ctx.GetByPos(pos)
+			if got == nil {
+				t.Fatal("no node found")
+			}
+			if pos < got.Pos() || pos >= got.End() {
+				t.Errorf("node span [%d, %d) does not cover %d", got.Pos(), got.End(), pos)
+			}
+
+			if got = clearSpans(got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unexpected node\n got: %#v\nwant: %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+// clearSpans drops source ranges of the node and its children, so it can be compared
+// with literals.
+func clearSpans(node cir.Node) cir.Node {
+	cir.Inspect(node, func(n cir.Node) bool {
+		n.SetSpan(token.NoPos, token.NoPos)
+		return true
+	})
+
+	return node
+}
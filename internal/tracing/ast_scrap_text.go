@@ -0,0 +1,124 @@
+package tracing
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/go/analysis"
+
+	"github.com/sirkon/cerrful/internal/cerrules"
+)
+
+// DefaultMessageMaxLength is the maximum length of error messages in runes if it is not configured.
+const DefaultMessageMaxLength = 64
+
+// messageNoise lists noise words error messages must not contain. Unlike forbidden terms
+// they are not configurable, fixes dropping them are built the same way though.
+var messageNoise = []ForbiddenTerm{
+	noiseTerm("failed to", WordPattern("failed to")),
+	noiseTerm("unable to", WordPattern("unable to")),
+	noiseTerm("could not", WordPattern("could not")),
+
+	// The word is fine in the middle of messages, like "unexpected joined error value".
+	noiseTerm("error", regexp.MustCompile(`(?i)^\s*error\b|\berror\s*$`)),
+}
+
+func noiseTerm(word string, pattern *regexp.Regexp) ForbiddenTerm {
+	drop := ""
+	return ForbiddenTerm{
+		Term:        word,
+		Pattern:     pattern,
+		Reason:      "an error is a failure already",
+		Replacement: &drop,
+	}
+}
+
+// checkMessage checks the text and style of the message of an annotation or of a constructor:
+//
+//	fmt.Errorf("Failed to read file.: %w", err) // uppercase, trailing punctuation and noise
+//	errors.New("bad value %d")                  // format verbs without arguments
+//
+// The arg is the message argument, it is nil if it is not known. noArgs is set for constructor
+// calls having the message as their only argument.
+func (e *ScrapEngine) checkMessage(pass *analysis.Pass, arg ast.Expr, msg string, noArgs bool, pos token.Position) {
+	if msg == "" {
+		return
+	}
+
+	if !startsLowercase(msg) {
+		e.r.Report(
+			cerrules.MessageMustStartLowercase(),
+			fmt.Sprintf("message %q must start with a lowercase letter", msg),
+			pos,
+		)
+	}
+
+	text := strings.TrimRight(msg, " \t")
+	switch {
+	case strings.HasSuffix(text, "\n"):
+		e.r.Report(cerrules.NoTrailingPunctuation(), fmt.Sprintf("message %q must not end with a newline", msg), pos)
+	case strings.TrimRight(text, ".,;:!?") != text:
+		e.r.Report(cerrules.NoTrailingPunctuation(), fmt.Sprintf("message %q must not end with punctuation", msg), pos)
+	}
+
+	if noise, ok := noiseOf(msg); ok {
+		text := fmt.Sprintf("message %q must not say %q, %s", msg, noise.Term, noise.Reason)
+		e.reportTerm(cerrules.NoNoiseWords(), text, pos, pass, arg, msg, noise)
+	}
+
+	if noArgs && len(formatVerbs(msg)) > 0 {
+		e.r.Report(
+			cerrules.NoFormatVerbsWithoutArgs(),
+			fmt.Sprintf("message %q has format verbs, but there are no arguments to format", msg),
+			pos,
+		)
+	}
+
+	if text := strings.TrimSuffix(msg, "\n"); strings.TrimFunc(text, unicode.IsSpace) != text {
+		e.r.Report(
+			cerrules.NoSurroundingWhitespace(),
+			fmt.Sprintf("message %q must not have leading or trailing whitespace", msg),
+			pos,
+		)
+	}
+
+	if n := utf8.RuneCountInString(msg); n > e.messageMaxLength {
+		e.r.Report(
+			cerrules.MessageMaxLength(),
+			fmt.Sprintf("message %q is %d characters long, %d at most are allowed", msg, n, e.messageMaxLength),
+			pos,
+		)
+	}
+
+	e.checkForbiddenTerms(pass, arg, msg, pos)
+}
+
+// startsLowercase checks if the message starts with a lowercase letter. Messages starting
+// with acronyms like "HTTP" or "ID", with format verbs or with non-letters are fine too.
+func startsLowercase(msg string) bool {
+	msg = strings.TrimLeftFunc(msg, unicode.IsSpace)
+	first, size := utf8.DecodeRuneInString(msg)
+	if !unicode.IsUpper(first) {
+		return true
+	}
+
+	next, _ := utf8.DecodeRuneInString(msg[size:])
+	return unicode.IsUpper(next) || unicode.IsDigit(next)
+}
+
+// noiseOf returns the noise term the message contains. Terms are looked up as whole words
+// ignoring case, the word "error" only counts at the start or at the end of the message.
+func noiseOf(msg string) (ForbiddenTerm, bool) {
+	for _, noise := range messageNoise {
+		if noise.Pattern.MatchString(msg) {
+			return noise, true
+		}
+	}
+
+	return ForbiddenTerm{}, false
+}
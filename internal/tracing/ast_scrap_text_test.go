@@ -0,0 +1,54 @@
+package tracing
+
+import "testing"
+
+func TestStartsLowercase(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want bool
+	}{
+		{msg: "read file", want: true},
+		{msg: "Read file", want: false},
+		{msg: "HTTP request", want: true},
+		{msg: "IDs lookup", want: true},
+		{msg: "S3 upload", want: true},
+		{msg: "A file", want: false},
+		{msg: "%s lookup", want: true},
+		{msg: "éclair", want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			if got := startsLowercase(tt.msg); got != tt.want {
+				t.Errorf("startsLowercase(%q) = %t, want %t", tt.msg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNoiseOf(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{msg: "read file"},
+		{msg: "Failed to read file", want: "failed to"},
+		{msg: "read file: unable  to open", want: "unable to"},
+		{msg: "read file: unable\tto open", want: "unable to"},
+		{msg: "could not", want: "could not"},
+		{msg: "read error", want: "error"},
+		{msg: "Error reading file", want: "error"},
+		{msg: "unexpected joined error value"},
+		{msg: "unexpected joined error %q"},
+		{msg: "read errors"},
+		{msg: "terror"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			if got, _ := noiseOf(tt.msg); got.Term != tt.want {
+				t.Errorf("noiseOf(%q) = %q, want %q", tt.msg, got.Term, tt.want)
+			}
+		})
+	}
+}
@@ -18,11 +18,6 @@ type Context struct {
 	tree *rbtree.Tree[*contextNodeSpan]
 }
 
-type ContextSpan struct {
-	start token.Pos
-	end   token.Pos
-}
-
 // GetByPos exits the most specific (innermost) node covering `pos`.
 func (c *Context) GetByPos(pos token.Pos) cir.Node {
 	probe := &contextNodeSpan{start: pos, end: pos}
@@ -37,7 +32,9 @@ func (c *Context) GetByPos(pos token.Pos) cir.Node {
 // The RB-tree orders only disjoint spans; any overlap is reported back via
 // InsertReturn, and we resolve it into a strict containment hierarchy.
 // All ordering/balancing is handled by the underlying rbtree.
-func (c *Context) Add(node cir.Node, s ContextSpan) {
-	span := &contextNodeSpan{start: s.start, end: s.end, node: node}
+// The span is set to the node as well.
+func (c *Context) Add(node cir.Node, start, end token.Pos) {
+	node.SetSpan(start, end)
+	span := &contextNodeSpan{start: start, end: end, node: node}
 	attachInto(c.tree, span)
 }
@@ -18,9 +18,35 @@ type ReportEngine struct {
 type Report struct {
 	Phase    ReportPhase
 	RuleCode cerrules.Rule
-	Pos      token.Pos
+	Pos      token.Position
 	Message  string
 	Details  any
+
+	// Related points at other places involved in the violation.
+	Related []ReportRelated
+
+	// Fixes are suggested fixes of the violation.
+	Fixes []ReportFix
+}
+
+// ReportRelated describes a place related to the reported violation.
+type ReportRelated struct {
+	Pos     token.Position
+	Message string
+}
+
+// ReportFix describes a suggested fix of the violation: edits of the source
+// to be applied together.
+type ReportFix struct {
+	Message string
+	Edits   []ReportEdit
+}
+
+// ReportEdit replaces the source text in [Pos, End) with NewText.
+type ReportEdit struct {
+	Pos     token.Position
+	End     token.Position
+	NewText string
 }
 
 // ReportPhase marks the tracing stage where a report was generated.
@@ -68,8 +94,24 @@ func (r *ReportEngine) Report(rep Report) {
 }
 
 // Report records a new rule violation under the bound phase.
-// It accepts a cerrules.Rule, human-readable message, and source position.
-func (rp *ReporterPhase) Report(rule cerrules.Rule, message string, pos token.Pos) {
+// It accepts a cerrules.Rule, human-readable message, source position and
+// optional places related to the violation.
+func (rp *ReporterPhase) Report(rule cerrules.Rule, message string, pos token.Position, related ...ReportRelated) {
+	if message == "" {
+		message = rule.Description()
+	}
+	rp.parent.Report(Report{
+		Phase:    rp.phase,
+		RuleCode: rule,
+		Message:  message,
+		Pos:      pos,
+		Related:  related,
+	})
+}
+
+// ReportWithFix records a new rule violation under the bound phase along with
+// a suggested fix of it.
+func (rp *ReporterPhase) ReportWithFix(rule cerrules.Rule, message string, pos token.Position, fix ReportFix) {
 	if message == "" {
 		message = rule.Description()
 	}
@@ -78,6 +120,7 @@ func (rp *ReporterPhase) Report(rule cerrules.Rule, message string, pos token.Po
 		RuleCode: rule,
 		Message:  message,
 		Pos:      pos,
+		Fixes:    []ReportFix{fix},
 	})
 }
 
@@ -91,15 +134,14 @@ func (r *ReportEngine) Reports() []Report {
 }
 
 // PrintSummary prints all collected reports in a compact, human-readable form.
-func (r *ReportEngine) PrintSummary(fset *token.FileSet) {
+func (r *ReportEngine) PrintSummary() {
 	for _, rep := range r.Reports() {
-		pos := fset.Position(rep.Pos)
 		fmt.Printf("[%s] %s — %s (%s:%d)\n",
 			rep.Phase,
 			rep.RuleCode,
 			rep.Message,
-			pos.Filename,
-			pos.Line,
+			rep.Pos.Filename,
+			rep.Pos.Line,
 		)
 	}
 }
@@ -0,0 +1,58 @@
+package tracing
+
+import (
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/ssa"
+)
+
+// BuildSSA builds SSA form for the package of the pass and returns its source functions,
+// including literals, in source order.
+//
+// It mirrors [golang.org/x/tools/go/analysis/passes/buildssa], except for the debug mode being
+// enabled: the interpreter relies on [ssa.DebugRef] instructions to tell what source variables
+// SSA values stand for.
+func BuildSSA(pass *analysis.Pass) []*ssa.Function {
+	prog := ssa.NewProgram(pass.Fset, ssa.BuilderMode(0))
+
+	// Create SSA packages for direct imports.
+	for _, p := range pass.Pkg.Imports() {
+		prog.CreatePackage(p, nil, nil, true)
+	}
+
+	// Create and build the primary package.
+	ssapkg := prog.CreatePackage(pass.Pkg, pass.Files, pass.TypesInfo, false)
+	ssapkg.SetDebugMode(true)
+	ssapkg.Build()
+
+	var funcs []*ssa.Function
+	var addAnons func(f *ssa.Function)
+	addAnons = func(f *ssa.Function) {
+		funcs = append(funcs, f)
+		for _, anon := range f.AnonFuncs {
+			addAnons(anon)
+		}
+	}
+
+	for _, f := range pass.Files {
+		for _, decl := range f.Decls {
+			fdecl, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+
+			obj, ok := pass.TypesInfo.Defs[fdecl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+
+			if fn := prog.FuncValue(obj); fn != nil {
+				addAnons(fn)
+			}
+		}
+	}
+
+	return funcs
+}
@@ -1,23 +1,73 @@
 package tracing
 
 import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"hash/maphash"
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
 	"golang.org/x/tools/go/ssa"
+
+	"github.com/sirkon/cerrful/internal/cerrules"
+	"github.com/sirkon/cerrful/internal/cir"
+)
+
+// syntheticErrName is used for errors having no explicit variable, e.g. direct returns.
+const syntheticErrName = "@err"
+
+const (
+	// maxBlockVisits bounds loop unrolling: a block can occur on a single path this many times at most.
+	maxBlockVisits = 2
+
+	// maxPathSteps bounds the number of blocks interpreted per function in path-sensitive mode.
+	// States are merged at join points once it is exhausted, see [State.Join].
+	maxPathSteps = 4096
 )
 
 // InterpretSSA interpret traversed SSA graph paths using explicit DFS stack.
-// Each path is explored once with isolated state copy.
-func InterpretSSA(fn *ssa.Function, ctx *Context) {
+//
+// Each path is explored with isolated state copy, so facts established on different
+// paths to the same block never mix. Loops are unrolled up to maxBlockVisits times
+// per path and paths reaching a block with a state seen there already are cut off.
+// If the function has too many paths to explore, the interpreter falls back to merging
+// states at join points until a fixpoint is reached.
+//
+// Exits and unused errors collected on all paths are checked once the tracing is done,
+// see [tracer.checkExits] and [tracer.checkDrops].
+//
+// The environment describes what is known beyond the function. Returns the summary of
+// the function, it is nil if the function returns no errors.
+func InterpretSSA(fn *ssa.Function, ctx *Context, r *ReportEngine, env Environment) *Summary {
 	if fn == nil || len(fn.Blocks) == 0 {
-		return
+		return nil
 	}
 
+	t := newTracer(fn, ctx, r.Phase(ReportTrace))
+	t.env = env
+
 	type frame struct {
 		block *ssa.BasicBlock
 		state *State
+		path  *pathStep
+	}
+
+	type seenKey struct {
+		block *ssa.BasicBlock
+		hash  uint64
 	}
 
-	stack := []frame{{fn.Blocks[0], NewState()}}
-	visited := make(map[*ssa.BasicBlock]bool)
+	stack := []frame{{fn.Blocks[0], t.initial(), nil}}
+	seed := maphash.MakeSeed()
+	seen := make(map[seenKey][]*State)
+	var finals []*State
+	joined := make(map[*ssa.BasicBlock]*State)
+	var steps int
 
 	for len(stack) > 0 {
 		// Pop frame
@@ -25,27 +75,267 @@ func InterpretSSA(fn *ssa.Function, ctx *Context) {
 		f := stack[n]
 		stack = stack[:n]
 
-		if visited[f.block] {
-			continue
+		state := f.state
+		if steps < maxPathSteps {
+			key := seenKey{block: f.block, hash: state.Hash(seed)}
+			if slices.ContainsFunc(seen[key], state.Equal) {
+				// The same state has been interpreted on another path already.
+				continue
+			}
+			seen[key] = append(seen[key], state)
+		} else {
+			if prev, ok := joined[f.block]; ok {
+				state = prev.Join(state)
+				if state.Equal(prev) {
+					continue
+				}
+			}
+			joined[f.block] = state
 		}
-		visited[f.block] = true
+		steps++
 
-		newState := f.state.Clone()
-		traceBlock(f.block, ctx, newState)
+		newState := state.Clone()
+		t.traceBlock(f.block, newState)
+		if len(f.block.Succs) == 0 {
+			finals = append(finals, newState)
+		}
 
 		// Push successors
-		for _, succ := range f.block.Succs {
-			stack = append(stack, frame{succ, newState.Clone()})
+		path := &pathStep{block: f.block, prev: f.path}
+		for i, succ := range f.block.Succs {
+			if steps < maxPathSteps && path.visits(succ) >= maxBlockVisits {
+				// Loop unrolling limit is reached.
+				continue
+			}
+
+			succState := newState.Clone()
+			if cond, ok := f.block.Instrs[len(f.block.Instrs)-1].(*ssa.If); ok {
+				// The first successor is the "true" branch.
+				if !t.handleIf(cond, succState, i == 0) {
+					// Known facts contradict the condition, the branch is unreachable.
+					continue
+				}
+			}
+			t.enter(f.block, succ, succState)
+			stack = append(stack, frame{succ, succState, path})
+		}
+	}
+
+	t.flush()
+	t.checkExits(finals, r.Phase(ReportState))
+	t.checkDrops(finals, r.Phase(ReportState))
+
+	return t.summarize(finals)
+}
+
+// pathStep is a node of a singly linked list representing a path through basic blocks.
+type pathStep struct {
+	block *ssa.BasicBlock
+	prev  *pathStep
+}
+
+// visits counts occurrences of the block on the path.
+func (p *pathStep) visits(block *ssa.BasicBlock) int {
+	var res int
+	for s := p; s != nil; s = s.prev {
+		if s.block == block {
+			res++
+		}
+	}
+
+	return res
+}
+
+// tracer keeps function-wide data needed to interpret its instructions.
+type tracer struct {
+	fn  *ssa.Function
+	ctx *Context
+	r   *ReporterPhase
+
+	// names maps SSA values to names of source variables they are bound to.
+	names map[ssa.Value]string
+
+	// origins describes positions errors came from: callee or parameter names.
+	origins map[token.Pos]string
+
+	// reported keeps issues reported already, the same instruction is usually
+	// interpreted on several paths.
+	reported map[tracerReport]bool
+
+	// checks keeps outcomes of error state checks. Unlike other issues a check
+	// is only reported if its outcome is known and the same on every path reaching it.
+	checks map[token.Pos]*tracerCheck
+
+	env Environment
+
+	// layers keeps layers of callees whose errors cross the boundary, by call positions.
+	layers map[token.Pos]string
+
+	// loggedBy keeps callees that logged errors before returning them, by call positions.
+	loggedBy map[token.Pos]string
+
+	// chains keeps errors compared in chains of || and && by positions of the comparisons.
+	chains map[token.Pos][]ssa.Value
+
+	// loggedExits and unloggedExits tell if errors returned were logged before on some paths.
+	loggedExits   bool
+	unloggedExits bool
+}
+
+type tracerReport struct {
+	rule cerrules.Rule
+	pos  token.Pos
+	msg  string
+}
+
+type tracerRelated struct {
+	pos token.Pos
+	msg string
+}
+
+type tracerCheck struct {
+	needed bool
+	msgs   []string
+}
+
+func newTracer(fn *ssa.Function, ctx *Context, r *ReporterPhase) *tracer {
+	t := &tracer{
+		fn:       fn,
+		ctx:      ctx,
+		r:        r,
+		names:    make(map[ssa.Value]string),
+		origins:  make(map[token.Pos]string),
+		reported: make(map[tracerReport]bool),
+		checks:   make(map[token.Pos]*tracerCheck),
+		layers:   make(map[token.Pos]string),
+		loggedBy: make(map[token.Pos]string),
+	}
+
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			ref, ok := instr.(*ssa.DebugRef)
+			if !ok || ref.IsAddr {
+				continue
+			}
+
+			id, ok := ref.Expr.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			if _, ok := ref.Object().(*types.Var); !ok {
+				continue
+			}
+
+			if _, ok := t.names[ref.X]; !ok {
+				t.names[ref.X] = id.Name
+			}
+		}
+	}
+	t.chains = comparisonChains(fn)
+
+	return t
+}
+
+// comparisonChains collects errors compared with nil in chains of || and &&, like
+//
+//	if err1 != nil || err2 != nil {
+//
+// by positions of the comparisons. The chain may be left before some of them are compared,
+// errors of the whole chain are checked all the same.
+func comparisonChains(fn *ssa.Function) map[token.Pos][]ssa.Value {
+	syntax := fn.Syntax()
+	if syntax == nil {
+		return nil
+	}
+
+	// Group positions of comparisons by chains, the outermost expression of a chain comes first.
+	chainOf := map[token.Pos]token.Pos{}
+	ast.Inspect(syntax, func(n ast.Node) bool {
+		e, ok := n.(*ast.BinaryExpr)
+		if !ok || (e.Op != token.LOR && e.Op != token.LAND) {
+			return true
+		}
+
+		var walk func(x ast.Expr)
+		walk = func(x ast.Expr) {
+			switch x := ast.Unparen(x).(type) {
+			case *ast.BinaryExpr:
+				switch x.Op {
+				case token.LOR, token.LAND:
+					walk(x.X)
+					walk(x.Y)
+				case token.EQL, token.NEQ:
+					if _, ok := chainOf[x.OpPos]; !ok {
+						chainOf[x.OpPos] = e.OpPos
+					}
+				}
+			}
+		}
+		walk(e)
+
+		return true
+	})
+	if len(chainOf) == 0 {
+		return nil
+	}
+
+	compared := map[token.Pos][]ssa.Value{}
+	var cmps []*ssa.BinOp
+	for _, b := range fn.Blocks {
+		for _, instr := range b.Instrs {
+			cmp, ok := instr.(*ssa.BinOp)
+			if !ok {
+				continue
+			}
+			chain, ok := chainOf[cmp.Pos()]
+			if !ok {
+				continue
+			}
+
+			x, y := cmp.X, cmp.Y
+			if isNil(x) {
+				x, y = y, x
+			}
+			if !isNil(y) || !IsError(x.Type()) {
+				continue
+			}
+
+			compared[chain] = append(compared[chain], x)
+			cmps = append(cmps, cmp)
+		}
+	}
+
+	res := map[token.Pos][]ssa.Value{}
+	for _, cmp := range cmps {
+		if chain := compared[chainOf[cmp.Pos()]]; len(chain) > 1 {
+			res[cmp.Pos()] = chain
+		}
+	}
+
+	return res
+}
+
+// initial returns the state at the function entry, where error parameters are the only known errors.
+func (t *tracer) initial() *State {
+	state := NewState()
+	for _, p := range t.fn.Params {
+		if !IsError(p.Type()) {
+			continue
 		}
+
+		state.Var(p).SetOrigin(p.Pos(), false)
+		t.origins[p.Pos()] = "parameter " + p.Name()
 	}
+
+	return state
 }
 
 // traceBlock performs branch-level interpretation of SSA instructions.
 // It updates the State according to detected operations on errors
 // and records transitions in tracing logs when appropriate.
-func traceBlock(block *ssa.BasicBlock, ctx *Context, state *State) {
+func (t *tracer) traceBlock(block *ssa.BasicBlock, state *State) {
 	for _, instr := range block.Instrs {
-		interpret(instr, ctx, state)
+		t.interpret(instr, state)
 	}
 }
 
@@ -55,41 +345,750 @@ func traceBlock(block *ssa.BasicBlock, ctx *Context, state *State) {
 //   - checks against nil,
 //   - calls to loggers or wrappers,
 //   - exits and propagations.
-func interpret(instr ssa.Instruction, ctx *Context, state *State) {
+func (t *tracer) interpret(instr ssa.Instruction, state *State) {
+	t.use(instr, state)
+
 	switch v := instr.(type) {
 
 	// Example: "t0 = call f()"
 	case *ssa.Call:
-		handleCall(v, ctx, state)
+		t.handleCall(v, state)
 
 	// Example: "if t1 != nil"
 	case *ssa.If:
-		handleIf(v, ctx, state)
+		// Facts established by conditions belong to branches, see handleIf.
 
 	// Example: "return err"
 	case *ssa.Return:
-		handleReturn(v, ctx, state)
+		t.handleReturn(v, state)
+
+	// Example: "panic(err)"
+	case *ssa.Panic:
+		t.handlePanic(v, state)
 
 	// Assignment or other generic instruction.
 	default:
-		handleAssign(v, ctx, state)
+		t.handleAssign(v, state)
 	}
 }
 
 // --- handlers ---
 
-func handleCall(call *ssa.Call, ctx *Context, state *State) {
-	// TODO: recognize error-producing calls, wrappers, and loggers.
+func (t *tracer) handleCall(call *ssa.Call, state *State) {
+	res := t.result(call)
+	if res != nil {
+		// The call may be interpreted again in a loop, drop facts of the previous iteration.
+		state.Reset(res)
+	}
+
+	switch node := t.ctx.GetByPos(call.Pos()).(type) {
+	case *cir.ExprNew:
+		if res != nil {
+			state.Var(res).SetNotNil(true)
+			state.Var(res).SetCreated()
+		}
+
+	case *cir.ExprWrap:
+		if res == nil {
+			return
+		}
+
+		state.Var(res).SetWrapped()
+		if src := t.lookup(node.Var, state); src != nil {
+			state.Derive(res, src)
+		}
+
+	case *cir.ExprJoin:
+		if res == nil {
+			return
+		}
+
+		// Errors wrapped with messages are annotated, the joined ones are combined into a new one.
+		// Either way, each of them is taken care of along with the result.
+		if node.Msg != "" {
+			state.Var(res).SetWrapped()
+		} else {
+			state.Var(res).SetCreated()
+		}
+		for _, v := range node.Vars {
+			if src := t.lookup(v, state); src != nil {
+				state.Derive(res, src)
+			}
+		}
+
+	case *cir.ExprAlias:
+		if res == nil {
+			return
+		}
+
+		if src := state.Lookup(node.Target); src != nil {
+			state.Alias(res, src)
+		}
+
+	case *cir.ExprNil:
+		// Errors of the call are documented to be nil, nothing to use.
+		if res != nil {
+			state.Var(res).SetUsed()
+		}
+
+	case *cir.Log:
+		if res != nil {
+			// Errors of loggers are of no interest.
+			state.Var(res).SetUsed()
+		}
+
+		v, ok := node.Var.(*cir.ExprVar)
+		if !ok {
+			return
+		}
+
+		if src := t.lookup(v, state); src != nil {
+			t.takeCare(src, false, call.Pos(), state)
+		}
+
+	default:
+		// Any other call producing an error.
+		if res == nil {
+			return
+		}
+
+		summary := t.summary(call)
+		if summary != nil {
+			// Transparent helpers return their arguments as is.
+			if i, ok := summary.Transparent(); ok && i < len(call.Call.Args) && IsError(call.Call.Args[i].Type()) {
+				state.Alias(res, call.Call.Args[i])
+				return
+			}
+		}
+
+		name, pkg := callee(call)
+		if summary != nil && pkg == t.fn.Pkg.Pkg && summary.Fresh() {
+			// Local helpers creating or annotating errors, like "return d.errorf(...)", are
+			// constructors and wrappers of their own.
+			if summary.Created {
+				state.Var(res).SetCreated()
+			} else {
+				state.Var(res).SetWrapped()
+			}
+			for _, arg := range call.Call.Args {
+				if IsError(arg.Type()) {
+					state.Derive(res, arg)
+				}
+			}
+			return
+		}
+
+		external := pkg != nil && pkg != t.fn.Pkg.Pkg && t.layer(pkg) != t.layer(t.fn.Pkg.Pkg)
+		state.Var(res).SetOrigin(call.Pos(), external)
+		t.origins[call.Pos()] = name
+		if external {
+			t.layers[call.Pos()] = t.layer(pkg)
+		}
+
+		if summary != nil && summary.Logged {
+			// The callee logged the error before returning it.
+			state.Var(res).SetTakenCare(false, call.Pos())
+			t.loggedBy[call.Pos()] = name
+		}
+	}
+}
+
+// use marks errors the instruction operates on as used. Debug references and Phi nodes
+// are not uses on their own: the former are not executable and the latter only pass
+// values to other instructions, see [tracer.enter].
+func (t *tracer) use(instr ssa.Instruction, state *State) {
+	switch instr.(type) {
+	case *ssa.DebugRef, *ssa.Phi:
+		return
+	}
+
+	for _, op := range instr.Operands(nil) {
+		if *op != nil && IsError((*op).Type()) {
+			state.Use(*op)
+		}
+	}
+
+	// Errors compared later in the chain are checked as well, if they are defined by now.
+	if cmp, ok := instr.(*ssa.BinOp); ok {
+		for _, v := range t.chains[cmp.Pos()] {
+			if state.Has(v) {
+				state.Use(v)
+			}
+		}
+	}
+}
+
+// handleIf applies facts established by the condition on the given branch. Returns false if
+// the branch contradicts known facts. Issues are only reported for the "true" branch,
+// so every check is reported once.
+func (t *tracer) handleIf(cond *ssa.If, state *State, branch bool) bool {
+	return t.applyCond(cond.Cond, state, branch, branch)
+}
+
+func (t *tracer) handleReturn(ret *ssa.Return, state *State) {
+	res := t.fn.Signature.Results()
+	if res.Len() == 0 || !IsError(res.At(res.Len()-1).Type()) {
+		return
+	}
+
+	v := ret.Results[len(ret.Results)-1]
+	if isNil(v) {
+		// Success paths are not modelled.
+		return
+	}
+
+	if notNil := state.Var(v).IsNotNil(); notNil == nil || *notNil {
+		if state.IsLogged(v) {
+			t.loggedExits = true
+		} else {
+			t.unloggedExits = true
+		}
+	}
+
+	t.takeCare(v, true, ret.Pos(), state)
+	state.Exit(ret.Pos(), state.Var(v))
 }
 
-func handleIf(cond *ssa.If, ctx *Context, state *State) {
-	// TODO: detect conditional checks like "if err != nil".
+func (t *tracer) handlePanic(p *ssa.Panic, state *State) {
+	v := p.X
+	if mi, ok := v.(*ssa.MakeInterface); ok {
+		v = mi.X
+	}
+	if !IsError(v.Type()) {
+		return
+	}
+
+	t.takeCare(v, false, p.Pos(), state)
 }
 
-func handleReturn(ret *ssa.Return, ctx *Context, state *State) {
-	// TODO: mark error as propagated or logged.
+func (t *tracer) handleAssign(instr ssa.Instruction, state *State) {
+	switch v := instr.(type) {
+	case *ssa.DebugRef:
+		// Keep track of what source variables stand for.
+		if v.IsAddr {
+			return
+		}
+		if _, ok := v.Object().(*types.Var); !ok {
+			return
+		}
+		if id, ok := v.Expr.(*ast.Ident); ok {
+			state.Bind(id.Name, v.X)
+		}
+
+	case *ssa.UnOp:
+		// Sentinel loads, such as "err := io.EOF".
+		sentinel, ok := t.sentinelRef(v)
+		if !ok {
+			return
+		}
+
+		state.Reset(v)
+		state.Var(v).SetNotNil(true)
+		state.Var(v).SetClass(sentinel, true)
+		state.Var(v).SetCreated()
+
+	case *ssa.MakeInterface:
+		// Typed errors constructed in place, such as "err = &fs.PathError{…}".
+		if !IsError(v.Type()) {
+			return
+		}
+		ref, ok := t.typed(v.X)
+		if !ok {
+			return
+		}
+
+		state.Reset(v)
+		state.Var(v).SetNotNil(true)
+		state.Var(v).SetClass(ref, true)
+		state.Var(v).SetCreated()
+	}
 }
 
-func handleAssign(instr ssa.Instruction, ctx *Context, state *State) {
-	// TODO: track variable assignments involving errors.
+// typed returns the type of the error value constructed in place: a composite literal,
+// its address or a conversion recorded as [cir.ExprType]. Constant conversions have no
+// positions in SSA, their types are taken as is.
+func (t *tracer) typed(v ssa.Value) (cir.Reference, bool) {
+	switch x := v.(type) {
+	case *ssa.Const:
+		if x.IsNil() {
+			return cir.Reference{}, false
+		}
+		return TypeRef(x.Type())
+
+	case *ssa.UnOp:
+		// Composite literals of value types are loaded from their allocations.
+		if x.Op != token.MUL {
+			return cir.Reference{}, false
+		}
+		if _, ok := x.X.(*ssa.Alloc); !ok {
+			return cir.Reference{}, false
+		}
+		v = x.X
+	}
+
+	if !v.Pos().IsValid() {
+		return cir.Reference{}, false
+	}
+	node, ok := t.ctx.GetByPos(v.Pos()).(*cir.ExprType)
+	if !ok {
+		return cir.Reference{}, false
+	}
+
+	return node.Ref, true
+}
+
+// enter applies changes happening when the control passes from one block to another:
+// Phi nodes of the target block inherit facts of values coming from the source block.
+func (t *tracer) enter(from, to *ssa.BasicBlock, state *State) {
+	idx := slices.Index(to.Preds, from)
+	if idx < 0 {
+		return
+	}
+
+	for _, instr := range to.Instrs {
+		phi, ok := instr.(*ssa.Phi)
+		if !ok {
+			// Phi nodes are always at the start of the block.
+			break
+		}
+		if !IsError(phi.Type()) {
+			continue
+		}
+
+		edge := phi.Edges[idx]
+		if isNil(edge) {
+			state.Reset(phi)
+			state.Var(phi).SetNotNil(false)
+			continue
+		}
+
+		state.Alias(phi, edge)
+	}
+}
+
+// --- helpers ---
+
+// applyCond applies facts established by the condition on the given branch.
+func (t *tracer) applyCond(cond ssa.Value, state *State, branch, report bool) bool {
+	switch v := cond.(type) {
+	case *ssa.UnOp:
+		if v.Op == token.NOT {
+			return t.applyCond(v.X, state, !branch, report)
+		}
+
+	case *ssa.BinOp:
+		if v.Op != token.EQL && v.Op != token.NEQ {
+			return true
+		}
+
+		// The value checked goes first, nil and sentinels are compared with.
+		x, y := v.X, v.Y
+		if isNil(x) || (t.isSentinelLoad(x) && !isNil(y)) {
+			x, y = y, x
+		}
+		if !IsError(x.Type()) {
+			return true
+		}
+
+		equal := (v.Op == token.EQL) == branch
+		if isNil(y) {
+			return t.setNotNil(x, !equal, v.Pos(), state, report)
+		}
+
+		if ref, ok := t.sentinelRef(y); ok && equal {
+			return t.setNotNil(x, true, v.Pos(), state, false) &&
+				t.setClass(x, ref, true, v.Pos(), state, report)
+		}
+
+	case *ssa.Call:
+		if !branch {
+			return true
+		}
+
+		switch node := t.ctx.GetByPos(v.Pos()).(type) {
+		case *cir.ErrorTypeIsCheck:
+			src := t.lookup(node.Src, state)
+			if src == nil {
+				return true
+			}
+
+			return t.setNotNil(src, true, v.Pos(), state, false) &&
+				t.setClass(src, node.Type, false, v.Pos(), state, report)
+
+		case *cir.ErrorTypeIsHelperCheck:
+			src := t.lookup(node.Src, state)
+			if src == nil {
+				return true
+			}
+
+			return t.setNotNil(src, true, v.Pos(), state, false) &&
+				t.setClass(src, node.Ref, false, v.Pos(), state, report)
+
+		case *cir.ErrorTypeExtract:
+			// errors.As(err, &target)
+			src := t.lookup(node.Src, state)
+			if src == nil || len(v.Call.Args) != 2 {
+				return true
+			}
+			target := v.Call.Args[1]
+			if mi, ok := target.(*ssa.MakeInterface); ok {
+				target = mi.X
+			}
+			// The target is a pointer to a variable of the type being looked for.
+			ref, ok := TypeRef(deref(target.Type()))
+			if !ok {
+				return true
+			}
+
+			return t.setNotNil(src, true, v.Pos(), state, false) &&
+				t.setClass(src, ref, false, v.Pos(), state, report)
+		}
+
+	case *ssa.Extract:
+		// Type assertions with ok and type switches:
+		//   if pe, ok := err.(*fs.PathError); ok
+		//   switch err.(type) { case *fs.PathError: … }
+		ta, ok := v.Tuple.(*ssa.TypeAssert)
+		if !ok || !ta.CommaOk || v.Index != 1 || !IsError(ta.X.Type()) || !branch {
+			return true
+		}
+		ref, ok := TypeRef(ta.AssertedType)
+		if !ok {
+			return true
+		}
+
+		// Asserting to a concrete type establishes the exact type of the error.
+		exact := !types.IsInterface(ta.AssertedType)
+		return t.setNotNil(ta.X, true, ta.Pos(), state, false) &&
+			t.setClass(ta.X, ref, exact, ta.Pos(), state, report)
+	}
+
+	return true
+}
+
+// takeCare marks the error and everything it was derived from as returned or logged.
+// Reports point at the place where the error was taken care of the first time as well.
+func (t *tracer) takeCare(v ssa.Value, isReturned bool, pos token.Pos, state *State) {
+	action := "logged"
+	if isReturned {
+		action = "returned"
+	}
+
+	for _, origin := range state.Origins(v) {
+		facts := state.Var(origin)
+		status := facts.SetTakenCare(isReturned, pos)
+		if status == StateErrorFactSetTakenCareStatusOK {
+			continue
+		}
+
+		name := t.name(origin)
+		if by, ok := t.loggedBy[facts.TakenCareAt()]; ok && status == StateErrorFactSetTakenCareStatusAlreadyLogged {
+			if isReturned {
+				// Callers are free to pass on errors logged by callees, these are reported there.
+				continue
+			}
+
+			related := []tracerRelated{
+				{pos: facts.TakenCareAt(), msg: fmt.Sprintf("error %s is logged by %s", name, by)},
+				{pos: pos, msg: "and logged again here"},
+			}
+			t.reportRelated(cerrules.NoLogAndReturn(), pos, related, "error %s is logged by %s already", name, by)
+			return
+		}
+
+		var msg, was string
+		switch status {
+		case StateErrorFactSetTakenCareStatusAlreadyLogged:
+			was = "logged"
+			if isReturned {
+				msg = "error %s is logged and then returned"
+			} else {
+				msg = "error %s is logged more than once"
+			}
+		case StateErrorFactSetTakeCareStatusAlreadyReturned:
+			was = "returned"
+			msg = "error %s is already returned"
+		}
+
+		var related []tracerRelated
+		if at := facts.TakenCareAt(); at.IsValid() {
+			related = append(related, tracerRelated{pos: at, msg: fmt.Sprintf("error %s is %s here", name, was)})
+		}
+		related = append(related, tracerRelated{pos: pos, msg: fmt.Sprintf("and %s here", action)})
+
+		t.reportRelated(cerrules.NoLogAndReturn(), pos, related, msg, name)
+		return
+	}
+}
+
+// setNotNil sets nil-ness of the error. Returns false if it contradicts known facts.
+func (t *tracer) setNotNil(v ssa.Value, notNil bool, pos token.Pos, state *State, report bool) bool {
+	var msg string
+	name := t.name(v)
+	status := state.Var(v).SetNotNil(notNil)
+	switch status {
+	case StateErrorFactSetNotNilStatusDuplicate:
+		msg = fmt.Sprintf("error %s is already known to be %s here", name, nilness(notNil))
+	case StateErrorFactSetNotNilStatusContradict:
+		msg = fmt.Sprintf("error %s is known to be %s here", name, nilness(!notNil))
+	}
+
+	if report {
+		t.check(pos, msg)
+	}
+
+	return status != StateErrorFactSetNotNilStatusContradict
+}
+
+// setClass adds a class to the error. Returns false if it contradicts known facts.
+func (t *tracer) setClass(v ssa.Value, class cir.Reference, exact bool, pos token.Pos, state *State, report bool) bool {
+	var msg string
+	name := t.name(v)
+	ref := refText(class)
+	status := state.Var(v).SetClass(class, exact)
+	switch status {
+	case StateErrorFactSetClassStatusDuplicate:
+		msg = fmt.Sprintf("error %s is already known to be of %s", name, ref)
+	case StateErrorFactSetClassStatusDuplicateUpgrade:
+		msg = fmt.Sprintf("error %s was checked to be of %s already, check for the exact value from the start", name, ref)
+	case StateErrorFactSetClassStatusDuplicateDowngrade:
+		msg = fmt.Sprintf("error %s is already known to be exactly %s", name, ref)
+	case StateErrorFactSetClassStatusExactImpossible:
+		msg = fmt.Sprintf("error %s is already known to be exactly of another value, it cannot be %s", name, ref)
+	}
+
+	if report {
+		t.check(pos, msg)
+	}
+
+	return status != StateErrorFactSetClassStatusExactImpossible
+}
+
+// check records an outcome of the error state check at the given position. An empty
+// message means the check was needed on the current path.
+func (t *tracer) check(pos token.Pos, msg string) {
+	c, ok := t.checks[pos]
+	if !ok {
+		c = &tracerCheck{}
+		t.checks[pos] = c
+	}
+
+	if msg == "" {
+		c.needed = true
+		return
+	}
+	if !slices.Contains(c.msgs, msg) {
+		c.msgs = append(c.msgs, msg)
+	}
+}
+
+// flush reports checks that were redundant in the same way on every path.
+func (t *tracer) flush() {
+	positions := slices.Sorted(maps.Keys(t.checks))
+	for _, pos := range positions {
+		c := t.checks[pos]
+		if c.needed || len(c.msgs) != 1 {
+			continue
+		}
+
+		t.report(cerrules.NoRedundantErrorCheck(), pos, "%s", c.msgs[0])
+	}
+}
+
+func (t *tracer) report(rule cerrules.Rule, pos token.Pos, format string, a ...any) {
+	t.reportRelated(rule, pos, nil, format, a...)
+}
+
+// reportRelated reports the issue along with other places involved in it.
+func (t *tracer) reportRelated(rule cerrules.Rule, pos token.Pos, related []tracerRelated, format string, a ...any) {
+	key := tracerReport{
+		rule: rule,
+		pos:  pos,
+		msg:  fmt.Sprintf(format, a...),
+	}
+	if t.reported[key] {
+		return
+	}
+	t.reported[key] = true
+
+	var rel []ReportRelated
+	for _, r := range related {
+		rel = append(rel, ReportRelated{
+			Pos:     t.fn.Prog.Fset.PositionFor(r.pos, false),
+			Message: r.msg,
+		})
+	}
+
+	t.r.Report(rule, key.msg, t.fn.Prog.Fset.PositionFor(pos, false), rel...)
+}
+
+// result returns the error value produced by the call, if any.
+func (t *tracer) result(call *ssa.Call) ssa.Value {
+	res := call.Call.Signature().Results()
+	if res.Len() == 0 || !IsError(res.At(res.Len()-1).Type()) {
+		return nil
+	}
+
+	if res.Len() == 1 {
+		return call
+	}
+
+	refs := call.Referrers()
+	if refs == nil {
+		return nil
+	}
+	for _, ref := range *refs {
+		if ex, ok := ref.(*ssa.Extract); ok && ex.Index == res.Len()-1 {
+			return ex
+		}
+	}
+
+	return nil
+}
+
+// lookup returns the value the CIR variable is bound to at the current point.
+func (t *tracer) lookup(v *cir.ExprVar, state *State) ssa.Value {
+	if v == nil || v.Name == "" {
+		return nil
+	}
+
+	return state.Lookup(v.Name)
+}
+
+// name returns the name of the variable the value is bound to, for reporting.
+func (t *tracer) name(v ssa.Value) string {
+	if name, ok := t.names[v]; ok {
+		return name
+	}
+
+	res := t.fn.Signature.Results()
+	if res.Len() > 0 {
+		if last := res.At(res.Len() - 1); last.Name() != "" && IsError(last.Type()) {
+			return last.Name()
+		}
+	}
+
+	return syntheticErrName
+}
+
+// summary returns the summary of the function called. Summaries of functions of the package
+// analyzed are looked up in local ones.
+func (t *tracer) summary(call *ssa.Call) *Summary {
+	fn := call.Call.StaticCallee()
+	if fn == nil {
+		return nil
+	}
+	obj, ok := fn.Object().(*types.Func)
+	if !ok || obj.Pkg() == nil {
+		return nil
+	}
+
+	lookup := t.env.Summaries
+	if obj.Pkg() == t.fn.Pkg.Pkg {
+		lookup = t.env.Local
+	}
+	if lookup == nil {
+		return nil
+	}
+
+	return lookup(obj.Origin())
+}
+
+// layer returns the layer the package belongs to.
+func (t *tracer) layer(pkg *types.Package) string {
+	if t.env.Layer == nil {
+		return pkg.Path()
+	}
+
+	return t.env.Layer(pkg)
+}
+
+// callee returns the name of the function called and its package. The package is nil
+// for dynamic calls of function values.
+func callee(call *ssa.Call) (string, *types.Package) {
+	if call.Call.IsInvoke() {
+		m := call.Call.Method
+		return m.FullName(), m.Pkg()
+	}
+
+	if fn := call.Call.StaticCallee(); fn != nil {
+		if obj, ok := fn.Object().(*types.Func); ok {
+			return obj.FullName(), obj.Pkg()
+		}
+		return fn.String(), nil
+	}
+
+	return call.Call.Value.String(), nil
+}
+
+func isNil(v ssa.Value) bool {
+	c, ok := v.(*ssa.Const)
+	return ok && c.IsNil()
+}
+
+// isSentinelLoad checks if the value is loaded from a sentinel, see [tracer.sentinelRef].
+func (t *tracer) isSentinelLoad(v ssa.Value) bool {
+	_, ok := t.sentinelRef(v)
+	return ok
+}
+
+// sentinelRef checks if the value is loaded from a sentinel: a package-level error variable
+// that is either configured to be one or named like one, ErrNotFound or errClosed, and never
+// changes. Only variables of the package analyzed can be seen to never change, they must be
+// initialized with constructors and never assigned after.
+func (t *tracer) sentinelRef(v ssa.Value) (cir.Reference, bool) {
+	load, ok := v.(*ssa.UnOp)
+	if !ok || load.Op != token.MUL {
+		return cir.Reference{}, false
+	}
+
+	g, ok := load.X.(*ssa.Global)
+	if !ok || g.Pkg == nil || !IsError(load.Type()) {
+		return cir.Reference{}, false
+	}
+
+	ref := Reference{
+		Package: g.Pkg.Pkg.Path(),
+		Name:    g.Name(),
+	}
+	switch {
+	case t.env.Sentinel != nil && t.env.Sentinel(ref):
+	case !isSentinelName(g.Name()):
+		return cir.Reference{}, false
+	case g.Pkg == t.fn.Pkg && !t.env.constants[g]:
+		return cir.Reference{}, false
+	}
+
+	return ref.CIR(), true
+}
+
+// isSentinelName checks if the name follows the naming convention of sentinels.
+func isSentinelName(name string) bool {
+	rest, ok := strings.CutPrefix(name, "Err")
+	if !ok {
+		rest, ok = strings.CutPrefix(name, "err")
+	}
+	if !ok {
+		return false
+	}
+
+	r, _ := utf8.DecodeRuneInString(rest)
+	return rest == "" || unicode.IsUpper(r)
+}
+
+func nilness(notNil bool) string {
+	if notNil {
+		return "not nil"
+	}
+
+	return "nil"
+}
+
+func refText(ref cir.Reference) string {
+	text, err := Reference(ref).MarshalText()
+	if err != nil {
+		return fmt.Sprintf("%+v", ref)
+	}
+
+	return string(text)
 }
@@ -0,0 +1,241 @@
+package tracing
+
+import (
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// maxSummaryRounds bounds the number of times summaries of functions calling each other
+// recursively are recomputed before they settle, see [settleSummaries].
+const maxSummaryRounds = 8
+
+// InterpretPackage interprets functions of a package with [InterpretSSA], callees before their
+// callers, so calls of local functions rely on their summaries. Functions calling each other
+// recursively are interpreted until their summaries settle, see [settleSummaries].
+//
+// Reports are added in the order of functions. Returns summaries of the functions.
+func InterpretPackage(fns []*ssa.Function, ctx *Context, r *ReportEngine, env Environment) map[*ssa.Function]*Summary {
+	env.constants = constantGlobals(fns)
+
+	local := map[*types.Func]*Summary{}
+	env.Local = func(fn *types.Func) *Summary {
+		return local[fn]
+	}
+
+	res := map[*ssa.Function]*Summary{}
+	reports := map[*ssa.Function]*ReportEngine{}
+	for _, scc := range callGraphSCCs(fns) {
+		settled := true
+		if isRecursive(scc) {
+			settled = settleSummaries(scc, ctx, env, local)
+		}
+
+		for _, fn := range scc {
+			reports[fn] = &ReportEngine{}
+			res[fn] = InterpretSSA(fn, ctx, reports[fn], env)
+		}
+
+		if !settled {
+			// Calls of the functions are taken as propagation of errors of unknown origin.
+			continue
+		}
+		for _, fn := range scc {
+			if obj, ok := fn.Object().(*types.Func); ok && res[fn] != nil {
+				local[obj] = res[fn]
+			}
+		}
+	}
+
+	for _, fn := range fns {
+		for _, rep := range reports[fn].Reports() {
+			r.Report(rep)
+		}
+	}
+
+	return res
+}
+
+// constantGlobals returns package-level error variables of the package initialized with
+// constructor calls or typed errors and never assigned by its functions.
+func constantGlobals(fns []*ssa.Function) map[*ssa.Global]bool {
+	if len(fns) == 0 || fns[0].Pkg == nil {
+		return nil
+	}
+
+	res := map[*ssa.Global]bool{}
+	stores := func(fn *ssa.Function, visit func(g *ssa.Global, val ssa.Value)) {
+		for _, block := range fn.Blocks {
+			for _, instr := range block.Instrs {
+				store, ok := instr.(*ssa.Store)
+				if !ok {
+					continue
+				}
+				if g, ok := store.Addr.(*ssa.Global); ok && IsError(store.Val.Type()) {
+					visit(g, store.Val)
+				}
+			}
+		}
+	}
+
+	if init := fns[0].Pkg.Func("init"); init != nil {
+		stores(init, func(g *ssa.Global, val ssa.Value) {
+			switch val.(type) {
+			case *ssa.Call, *ssa.MakeInterface:
+				res[g] = true
+			}
+		})
+	}
+	for _, fn := range fns {
+		stores(fn, func(g *ssa.Global, val ssa.Value) {
+			delete(res, g)
+		})
+	}
+
+	return res
+}
+
+// settleSummaries computes summaries of functions calling each other recursively. They are
+// assumed to create errors until their interpretation tells otherwise. Returns false if the
+// summaries do not settle within maxSummaryRounds, they are dropped then.
+func settleSummaries(scc []*ssa.Function, ctx *Context, env Environment, local map[*types.Func]*Summary) bool {
+	var objs []*types.Func
+	for _, fn := range scc {
+		if obj, ok := fn.Object().(*types.Func); ok {
+			objs = append(objs, obj)
+			local[obj] = &Summary{Created: true}
+		}
+	}
+
+	for range maxSummaryRounds {
+		settled := true
+		for _, fn := range scc {
+			var scratch ReportEngine
+			summary := InterpretSSA(fn, ctx, &scratch, env)
+
+			obj, ok := fn.Object().(*types.Func)
+			if !ok {
+				continue
+			}
+			if prev := local[obj]; !equalSummaries(prev, summary) {
+				settled = false
+			}
+			if summary != nil {
+				local[obj] = summary
+			} else {
+				delete(local, obj)
+			}
+		}
+
+		if settled {
+			return true
+		}
+	}
+
+	for _, obj := range objs {
+		delete(local, obj)
+	}
+
+	return false
+}
+
+func equalSummaries(a, b *Summary) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return a.String() == b.String()
+}
+
+// callGraphSCCs splits functions into strongly connected components of their static calls
+// of each other. Components go in reverse topological order: callees before their callers.
+func callGraphSCCs(fns []*ssa.Function) [][]*ssa.Function {
+	known := map[*ssa.Function]bool{}
+	for _, fn := range fns {
+		known[fn] = true
+	}
+
+	// Tarjan's algorithm, components are complete once their roots are left.
+	var (
+		res   [][]*ssa.Function
+		stack []*ssa.Function
+		index = map[*ssa.Function]int{}
+		low   = map[*ssa.Function]int{}
+		on    = map[*ssa.Function]bool{}
+	)
+	var visit func(fn *ssa.Function)
+	visit = func(fn *ssa.Function) {
+		index[fn] = len(index)
+		low[fn] = index[fn]
+		stack = append(stack, fn)
+		on[fn] = true
+
+		for _, callee := range staticCallees(fn, known) {
+			if _, ok := index[callee]; !ok {
+				visit(callee)
+				low[fn] = min(low[fn], low[callee])
+			} else if on[callee] {
+				low[fn] = min(low[fn], index[callee])
+			}
+		}
+
+		if low[fn] != index[fn] {
+			return
+		}
+
+		var scc []*ssa.Function
+		for {
+			n := len(stack) - 1
+			top := stack[n]
+			stack = stack[:n]
+			on[top] = false
+			scc = append(scc, top)
+			if top == fn {
+				break
+			}
+		}
+		res = append(res, scc)
+	}
+
+	for _, fn := range fns {
+		if _, ok := index[fn]; !ok {
+			visit(fn)
+		}
+	}
+
+	return res
+}
+
+// isRecursive checks if functions of the component call each other or themselves.
+func isRecursive(scc []*ssa.Function) bool {
+	if len(scc) > 1 {
+		return true
+	}
+
+	fn := scc[0]
+	for _, callee := range staticCallees(fn, map[*ssa.Function]bool{fn: true}) {
+		if callee == fn {
+			return true
+		}
+	}
+
+	return false
+}
+
+// staticCallees returns known functions called by the function statically.
+func staticCallees(fn *ssa.Function, known map[*ssa.Function]bool) []*ssa.Function {
+	var res []*ssa.Function
+	for _, block := range fn.Blocks {
+		for _, instr := range block.Instrs {
+			call, ok := instr.(*ssa.Call)
+			if !ok {
+				continue
+			}
+			if callee := call.Call.StaticCallee(); known[callee] {
+				res = append(res, callee)
+			}
+		}
+	}
+
+	return res
+}
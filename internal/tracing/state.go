@@ -2,34 +2,158 @@ package tracing
 
 import (
 	"go/token"
+	"hash/maphash"
+	"maps"
+	"slices"
+
+	"golang.org/x/tools/go/ssa"
 )
 
 // State for tracking interpretation states.
+//
+// Facts are keyed by SSA values, so shadowed variables and reassignments never
+// mix up: every assignment of an error variable produces a new value. Source names
+// are only used to resolve CIR references to variables and for reporting.
 type State struct {
-	errors map[string]*StateErrorFacts
+	errors map[ssa.Value]*StateErrorFacts
 	exits  map[token.Pos]*StateErrorFacts
+
+	// derived keeps errors the given one was derived from, i.e. wrapped or passed through.
+	derived map[ssa.Value][]ssa.Value
+
+	// bound keeps values source variable names are bound to at the current point of a path.
+	bound map[string]ssa.Value
 }
 
 // NewState is [State] constructor.
 func NewState() *State {
-	return &State{}
+	return &State{
+		errors:  make(map[ssa.Value]*StateErrorFacts),
+		exits:   make(map[token.Pos]*StateErrorFacts),
+		derived: make(map[ssa.Value][]ssa.Value),
+		bound:   make(map[string]ssa.Value),
+	}
 }
 
-// Var access an errors controller for the given variable.
-func (s *State) Var(name string) *StateErrorFacts {
-	v, ok := s.errors[name]
+// Var access an errors controller for the given value.
+func (s *State) Var(v ssa.Value) *StateErrorFacts {
+	f, ok := s.errors[v]
 	if !ok {
-		v = &StateErrorFacts{}
-		s.errors[name] = v
+		f = &StateErrorFacts{}
+		s.errors[v] = f
+	}
+
+	return f
+}
+
+// Has checks if anything is known about the value.
+func (s *State) Has(v ssa.Value) bool {
+	_, ok := s.errors[v]
+	return ok
+}
+
+// Reset drops everything known about the value. It is used when the instruction
+// defining the value is interpreted again, i.e. in loops.
+func (s *State) Reset(v ssa.Value) {
+	delete(s.errors, v)
+	delete(s.derived, v)
+}
+
+// Alias makes the value to share everything known about the source. It is used
+// for Phi nodes inheriting facts from the value of an incoming edge.
+func (s *State) Alias(v, src ssa.Value) {
+	s.Reset(v)
+	if f, ok := s.errors[src]; ok {
+		s.errors[v] = f.Clone()
+	}
+	if d, ok := s.derived[src]; ok {
+		s.derived[v] = slices.Clone(d)
+	}
+	s.Derive(v, src)
+}
+
+// Derive records the error to be derived from the given source.
+func (s *State) Derive(v, src ssa.Value) {
+	if v == src || slices.Contains(s.derived[v], src) {
+		return
+	}
+
+	s.derived[v] = append(s.derived[v], src)
+}
+
+// Origins returns the given value and all errors it was derived from.
+func (s *State) Origins(v ssa.Value) []ssa.Value {
+	res := []ssa.Value{v}
+	seen := map[ssa.Value]bool{v: true}
+	for i := 0; i < len(res); i++ {
+		for _, src := range s.derived[res[i]] {
+			if seen[src] {
+				continue
+			}
+
+			seen[src] = true
+			res = append(res, src)
+		}
+	}
+
+	return res
+}
+
+// Use marks the error and all errors it was derived from as used.
+func (s *State) Use(v ssa.Value) {
+	for _, src := range s.Origins(v) {
+		s.Var(src).SetUsed()
 	}
+}
+
+// IsLogged checks if the error or any error it was derived from is logged on the path.
+func (s *State) IsLogged(v ssa.Value) bool {
+	for _, src := range s.Origins(v) {
+		if f, ok := s.errors[src]; ok && f.IsLogged() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Unused returns errors having no use on the path.
+func (s *State) Unused() []ssa.Value {
+	var res []ssa.Value
+	for v, f := range s.errors {
+		if !f.IsUsed() {
+			res = append(res, v)
+		}
+	}
+
+	return res
+}
+
+// Exit records facts of the error returned at the given position.
+func (s *State) Exit(pos token.Pos, facts *StateErrorFacts) {
+	s.exits[pos] = facts.Clone()
+}
+
+// Exits returns facts of errors returned on the path, by return positions.
+func (s *State) Exits() map[token.Pos]*StateErrorFacts {
+	return s.exits
+}
 
-	return v
+// Bind binds the source variable name to the value.
+func (s *State) Bind(name string, v ssa.Value) {
+	s.bound[name] = v
+}
+
+// Lookup returns the value the source variable name is bound to. Returns nil if
+// there is no such name.
+func (s *State) Lookup(name string) ssa.Value {
+	return s.bound[name]
 }
 
 func (s *State) Clone() *State {
 	ns := NewState()
 
-	ns.errors = make(map[string]*StateErrorFacts, len(s.errors))
+	ns.errors = make(map[ssa.Value]*StateErrorFacts, len(s.errors))
 	for k, v := range s.errors {
 		ns.errors[k] = v.Clone()
 	}
@@ -39,5 +163,116 @@ func (s *State) Clone() *State {
 		ns.exits[k] = v.Clone()
 	}
 
+	ns.derived = make(map[ssa.Value][]ssa.Value, len(s.derived))
+	for k, v := range s.derived {
+		ns.derived[k] = slices.Clone(v)
+	}
+
+	ns.bound = maps.Clone(s.bound)
+
+	return ns
+}
+
+// Equal checks if both states are the same.
+func (s *State) Equal(other *State) bool {
+	eq := func(a, b *StateErrorFacts) bool { return a.Equal(b) }
+
+	return maps.EqualFunc(s.errors, other.errors, eq) &&
+		maps.EqualFunc(s.exits, other.exits, eq) &&
+		maps.EqualFunc(s.derived, other.derived, slices.Equal) &&
+		maps.Equal(s.bound, other.bound)
+}
+
+// Hash returns a hash of the state: equal states have equal hashes. It lets to look for
+// a state among many ones without comparing it with each of them.
+func (s *State) Hash(seed maphash.Seed) uint64 {
+	// Hashes of entries are summed up, so the result does not depend on the order of iteration.
+	var res uint64
+	entry := func(tag byte, write func(h *maphash.Hash)) {
+		var h maphash.Hash
+		h.SetSeed(seed)
+		_ = h.WriteByte(tag)
+		write(&h)
+		res += h.Sum64()
+	}
+
+	for k, v := range s.errors {
+		entry('e', func(h *maphash.Hash) {
+			maphash.WriteComparable(h, k)
+			v.hash(h)
+		})
+	}
+	for k, v := range s.exits {
+		entry('x', func(h *maphash.Hash) {
+			maphash.WriteComparable(h, k)
+			v.hash(h)
+		})
+	}
+	for k, v := range s.derived {
+		entry('d', func(h *maphash.Hash) {
+			maphash.WriteComparable(h, k)
+			for _, src := range v {
+				maphash.WriteComparable(h, src)
+			}
+		})
+	}
+	for k, v := range s.bound {
+		entry('b', func(h *maphash.Hash) {
+			_, _ = h.WriteString(k)
+			maphash.WriteComparable(h, v)
+		})
+	}
+
+	return res
+}
+
+// Join returns a state keeping only what is known for both of the given ones. Used
+// to merge states at control flow join points, see [StateErrorFacts.Join] for details.
+//
+// Exits are different: they are facts collected at return sites rather than the current
+// knowledge, so they are united. Errors known on one side only are defined on its paths
+// alone and nothing is known about them beyond whether they were used, this is kept so
+// errors left unused on some path are still found, see [tracer.checkDrops].
+func (s *State) Join(other *State) *State {
+	ns := NewState()
+
+	for k, v := range s.errors {
+		if ov, ok := other.errors[k]; ok {
+			ns.errors[k] = v.Join(ov)
+		} else {
+			ns.errors[k] = &StateErrorFacts{used: v.used}
+		}
+	}
+	for k, v := range other.errors {
+		if _, ok := s.errors[k]; !ok {
+			ns.errors[k] = &StateErrorFacts{used: v.used}
+		}
+	}
+
+	for k, v := range s.exits {
+		ns.exits[k] = v.Clone()
+	}
+	for k, v := range other.exits {
+		if nv, ok := ns.exits[k]; ok {
+			ns.exits[k] = nv.Join(v)
+		} else {
+			ns.exits[k] = v.Clone()
+		}
+	}
+
+	for k, v := range s.derived {
+		for _, src := range v {
+			if slices.Contains(other.derived[k], src) {
+				ns.derived[k] = append(ns.derived[k], src)
+			}
+		}
+	}
+
+	for k, v := range s.bound {
+		if other.bound[k] == v {
+			ns.bound[k] = v
+		}
+	}
+
 	return ns
 }
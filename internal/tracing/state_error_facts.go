@@ -1,6 +1,8 @@
 package tracing
 
 import (
+	"go/token"
+	"hash/maphash"
 	"maps"
 
 	"github.com/sirkon/cerrful/internal/cir"
@@ -11,7 +13,21 @@ type StateErrorFacts struct {
 	notNil    *bool
 	takenCare *bool
 	wrapped   bool
-	classOf   map[cir.Reference]bool
+
+	// takenCareAt is where the error was returned or logged.
+	takenCareAt token.Pos
+
+	classOf map[cir.Reference]bool
+
+	// origin is where the error came from: a call or a parameter.
+	origin   token.Pos
+	external bool
+
+	// created is set for errors created locally: constructors, sentinels, etc.
+	created bool
+
+	// used is set once the error is checked, logged, returned or passed on anyhow.
+	used bool
 }
 
 // --- Service --------------------------------------------------------------------------------------------------------
@@ -19,11 +35,130 @@ type StateErrorFacts struct {
 // Clone returns a full copy of the state.
 func (f *StateErrorFacts) Clone() *StateErrorFacts {
 	return &StateErrorFacts{
-		notNil:    f.notNil,
-		takenCare: f.takenCare,
-		wrapped:   f.wrapped,
-		classOf:   maps.Clone(f.classOf),
+		notNil:      f.notNil,
+		takenCare:   f.takenCare,
+		takenCareAt: f.takenCareAt,
+		wrapped:     f.wrapped,
+		classOf:     maps.Clone(f.classOf),
+		origin:      f.origin,
+		external:    f.external,
+		created:     f.created,
+		used:        f.used,
+	}
+}
+
+// Equal checks if both facts are the same.
+func (f *StateErrorFacts) Equal(other *StateErrorFacts) bool {
+	return equalBoolPtr(f.notNil, other.notNil) &&
+		equalBoolPtr(f.takenCare, other.takenCare) &&
+		f.takenCareAt == other.takenCareAt &&
+		f.wrapped == other.wrapped &&
+		maps.Equal(f.classOf, other.classOf) &&
+		f.origin == other.origin &&
+		f.external == other.external &&
+		f.created == other.created &&
+		f.used == other.used
+}
+
+// Join returns facts that hold for both of the given ones. This is a join operation of
+// a lattice where each fact either keeps its value if both sides agree on it or becomes
+// unknown otherwise:
+//
+//   - notNil and takenCare remain known only if equal, the position of the latter is kept only
+//     if it is the same as well.
+//   - wrapped remains only if both were wrapped.
+//   - classOf is an intersection of classes, a class remains exact only if it was exact on both sides.
+//   - origin remains only if equal, the error is external if it is external on any side and it is
+//     created only if it was created on both sides.
+//   - The error is used only if it was used on both sides.
+func (f *StateErrorFacts) Join(other *StateErrorFacts) *StateErrorFacts {
+	res := &StateErrorFacts{
+		wrapped:  f.wrapped && other.wrapped,
+		external: f.external || other.external,
+		created:  f.created && other.created,
+		used:     f.used && other.used,
+	}
+
+	if f.origin == other.origin {
+		res.origin = f.origin
+	}
+
+	if equalBoolPtr(f.notNil, other.notNil) {
+		res.notNil = f.notNil
 	}
+	if equalBoolPtr(f.takenCare, other.takenCare) {
+		res.takenCare = f.takenCare
+		if f.takenCareAt == other.takenCareAt {
+			res.takenCareAt = f.takenCareAt
+		}
+	}
+
+	for class, exact := range f.classOf {
+		otherExact, ok := other.classOf[class]
+		if !ok {
+			continue
+		}
+
+		if res.classOf == nil {
+			res.classOf = make(map[cir.Reference]bool)
+		}
+		res.classOf[class] = exact && otherExact
+	}
+
+	return res
+}
+
+// hash writes facts into the hash, equal facts are written the same way, see [State.Hash].
+func (f *StateErrorFacts) hash(h *maphash.Hash) {
+	// Classes are summed up, so the result does not depend on the order of iteration.
+	var classes uint64
+	for class, exact := range f.classOf {
+		classes += maphash.Comparable(h.Seed(), class)
+		if exact {
+			classes++
+		}
+	}
+
+	maphash.WriteComparable(h, struct {
+		notNil      int8
+		takenCare   int8
+		takenCareAt token.Pos
+		wrapped     bool
+		classes     uint64
+		origin      token.Pos
+		external    bool
+		created     bool
+		used        bool
+	}{
+		notNil:      boolPtrHash(f.notNil),
+		takenCare:   boolPtrHash(f.takenCare),
+		takenCareAt: f.takenCareAt,
+		wrapped:     f.wrapped,
+		classes:     classes,
+		origin:      f.origin,
+		external:    f.external,
+		created:     f.created,
+		used:        f.used,
+	})
+}
+
+func boolPtrHash(v *bool) int8 {
+	switch {
+	case v == nil:
+		return 0
+	case *v:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func equalBoolPtr(a, b *bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
 }
 
 // --- Setters --------------------------------------------------------------------------------------------------------
@@ -48,14 +183,14 @@ func (f *StateErrorFacts) SetNotNil(isnotnil bool) StateErrorFactSetNotNilStatus
 	}
 }
 
-// SetTakenCare sets a variable as it was taken care of. The isReturned thing
-// sets it to returned (true), or logged (false).
+// SetTakenCare sets a variable as it was taken care of at the given position. The isReturned thing
+// sets it to returned (true), or logged (false). The position of the first one is kept.
 //
 // Possible issues are:
 //
 //   - Logging and returning must not intermix.
 //   - Logging can be done only once.
-func (f *StateErrorFacts) SetTakenCare(isReturned bool) StateErrorFactSetTakenCareStatus {
+func (f *StateErrorFacts) SetTakenCare(isReturned bool, pos token.Pos) StateErrorFactSetTakenCareStatus {
 	if f.takenCare != nil {
 		if *f.takenCare {
 			// It was returned before.
@@ -66,6 +201,7 @@ func (f *StateErrorFacts) SetTakenCare(isReturned bool) StateErrorFactSetTakenCa
 	}
 
 	f.takenCare = &isReturned
+	f.takenCareAt = pos
 	return StateErrorFactSetTakenCareStatusOK
 }
 
@@ -89,6 +225,9 @@ func (f *StateErrorFacts) SetClass(class cir.Reference, exact bool) StateErrorFa
 			}
 		}
 
+		if f.classOf == nil {
+			f.classOf = make(map[cir.Reference]bool)
+		}
 		f.classOf[class] = exact
 		return StateErrorFactSetClassStatusOK
 	}
@@ -112,6 +251,23 @@ func (f *StateErrorFacts) SetWrapped() {
 	f.wrapped = true
 }
 
+// SetOrigin sets where the error came from. The external flag is set for errors returned by
+// functions of other layers.
+func (f *StateErrorFacts) SetOrigin(pos token.Pos, external bool) {
+	f.origin = pos
+	f.external = external
+}
+
+// SetCreated marks the error as created locally.
+func (f *StateErrorFacts) SetCreated() {
+	f.created = true
+}
+
+// SetUsed marks the error as used: checked, logged, returned or passed on anyhow.
+func (f *StateErrorFacts) SetUsed() {
+	f.used = true
+}
+
 // --- Getters --------------------------------------------------------------------------------------------------------
 
 // IsNotNil exits if the variable is known to be nil (false) or not nil (true). It exits nil
@@ -129,6 +285,12 @@ func (f *StateErrorFacts) IsTakenCare() bool {
 	return true
 }
 
+// TakenCareAt returns the position where the variable was taken care of. Returns [token.NoPos]
+// if it is unknown.
+func (f *StateErrorFacts) TakenCareAt() token.Pos {
+	return f.takenCareAt
+}
+
 // IsLogged exits true if this variable has been logged already.
 func (f *StateErrorFacts) IsLogged() bool {
 	if f.takenCare == nil {
@@ -169,6 +331,33 @@ func (f *StateErrorFacts) IsWrapped() bool {
 	return f.wrapped
 }
 
+// Origin returns the position of the call or parameter the error came from. Returns [token.NoPos]
+// if it is unknown or the error was created locally.
+func (f *StateErrorFacts) Origin() token.Pos {
+	return f.origin
+}
+
+// IsExternal returns true if the error came from a function of another layer.
+func (f *StateErrorFacts) IsExternal() bool {
+	return f.external
+}
+
+// IsCreated returns true if the error was created locally.
+func (f *StateErrorFacts) IsCreated() bool {
+	return f.created
+}
+
+// IsUsed returns true if the error was used anyhow.
+func (f *StateErrorFacts) IsUsed() bool {
+	return f.used
+}
+
+// IsBare returns true if the error is propagated as is: it came from somewhere else
+// and was not annotated.
+func (f *StateErrorFacts) IsBare() bool {
+	return f.origin.IsValid() && !f.created && !f.wrapped
+}
+
 // --- Types for status setting part ----------------------------------------------------------------------------------
 
 // StateErrorFactSetNotNilStatus represents possible issues that can be arisen when NotNil status was being set.
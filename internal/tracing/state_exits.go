@@ -0,0 +1,168 @@
+package tracing
+
+import (
+	"fmt"
+	"go/token"
+	"maps"
+	"slices"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/sirkon/cerrful/internal/cerrules"
+)
+
+// checkExits analyzes errors returned by the function on all paths:
+//
+//   - Errors of calls of other layers cross a semantic boundary and must be annotated before
+//     they are returned (CER010).
+//   - Local errors returned as is from several return sites along with an error of another
+//     layer must be annotated as well (CER040).
+//   - Several different errors returned as is make it impossible to tell which operation failed,
+//     so all of them must be annotated (CER030).
+//   - A local error can be returned as is only from a single return site (CER020).
+//
+// Each return site gets at most one report, in the order given above.
+func (t *tracer) checkExits(finals []*State, r *ReporterPhase) {
+	// Collect distinct facts of returned errors for every return site.
+	exits := map[token.Pos][]*StateErrorFacts{}
+	for _, state := range finals {
+		for pos, facts := range state.Exits() {
+			if !slices.ContainsFunc(exits[pos], facts.Equal) {
+				exits[pos] = append(exits[pos], facts)
+			}
+		}
+	}
+
+	// Group return sites of bare errors by their origins.
+	sites := map[token.Pos][]token.Pos{}
+	external := map[token.Pos]token.Pos{}
+	local := map[token.Pos]token.Pos{}
+	for _, pos := range slices.Sorted(maps.Keys(exits)) {
+		for _, facts := range exits[pos] {
+			if !facts.IsBare() {
+				continue
+			}
+
+			origin := facts.Origin()
+			if !slices.Contains(sites[origin], pos) {
+				sites[origin] = append(sites[origin], pos)
+			}
+			if facts.IsExternal() {
+				if _, ok := external[pos]; !ok {
+					external[pos] = origin
+				}
+			} else if _, ok := local[pos]; !ok {
+				local[pos] = origin
+			}
+		}
+	}
+
+	reported := map[token.Pos]bool{}
+	report := func(rule cerrules.Rule, pos token.Pos, format string, a ...any) {
+		if reported[pos] {
+			return
+		}
+
+		reported[pos] = true
+		r.Report(rule, fmt.Sprintf(format, a...), t.fn.Prog.Fset.PositionFor(pos, false))
+	}
+
+	origins := slices.Sorted(maps.Keys(sites))
+
+	for _, pos := range slices.Sorted(maps.Keys(external)) {
+		report(
+			cerrules.AnnotateExternal(),
+			pos,
+			"error of %s is returned as is across the boundary between %s and %s, annotate it",
+			t.origins[external[pos]],
+			t.layers[external[pos]],
+			t.layer(t.fn.Pkg.Pkg),
+		)
+	}
+
+	if len(external) > 0 && len(local) > 1 {
+		first := slices.Sorted(maps.Keys(external))[0]
+		for _, pos := range slices.Sorted(maps.Keys(local)) {
+			report(
+				cerrules.AnnotationRequiredForExternalAndMultiLocal(),
+				pos,
+				"error of %s is returned as is from one of %d places along with the error of %s, annotate them",
+				t.origins[local[pos]],
+				len(local),
+				t.origins[external[first]],
+			)
+		}
+	}
+
+	if len(origins) > 1 {
+		for _, origin := range origins {
+			for _, pos := range sites[origin] {
+				report(
+					cerrules.MultiReturnMustAnnotate(),
+					pos,
+					"error of %s is returned as is along with %d more errors, annotate them to tell which operation failed",
+					t.origins[origin],
+					len(origins)-1,
+				)
+			}
+		}
+	}
+
+	for _, origin := range origins {
+		if len(sites[origin]) < 2 {
+			continue
+		}
+
+		for _, pos := range sites[origin] {
+			report(
+				cerrules.SingleLocalPassthrough(),
+				pos,
+				"error of %s is returned as is from %d places, annotate them",
+				t.origins[origin],
+				len(sites[origin]),
+			)
+		}
+	}
+}
+
+// checkDrops reports errors assigned to variables and never used on some path: not checked,
+// logged, returned or passed on (CER000). Errors dropped right away, like in
+//
+//	_ = os.Remove(path)
+//
+// are reported by [ScrapEngine] as they never get to variables.
+func (t *tracer) checkDrops(finals []*State, r *ReporterPhase) {
+	drops := map[token.Pos]string{}
+	for _, state := range finals {
+		for _, v := range state.Unused() {
+			name, ok := t.names[v]
+			if !ok {
+				continue
+			}
+
+			if pos := callPos(v); pos.IsValid() {
+				drops[pos] = name
+			}
+		}
+	}
+
+	for _, pos := range slices.Sorted(maps.Keys(drops)) {
+		r.Report(
+			cerrules.NoSilentDrop(),
+			fmt.Sprintf("error %s is never checked, logged, returned or passed on along some path", drops[pos]),
+			t.fn.Prog.Fset.PositionFor(pos, false),
+		)
+	}
+}
+
+// callPos returns the position of the call the value is a result of.
+func callPos(v ssa.Value) token.Pos {
+	if ex, ok := v.(*ssa.Extract); ok {
+		v = ex.Tuple
+	}
+	if call, ok := v.(*ssa.Call); ok {
+		return call.Pos()
+	}
+
+	return token.NoPos
+}
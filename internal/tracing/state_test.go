@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/sirkon/cerrful/internal/cir"
+)
+
+func TestStateJoin(t *testing.T) {
+	value := func() ssa.Value {
+		return ssa.NewConst(nil, types.Universe.Lookup("error").Type())
+	}
+	err, only, src, other := value(), value(), value(), value()
+
+	eof := cir.Reference{Package: "io", Name: "EOF"}
+	unexpected := cir.Reference{Package: "io", Name: "ErrUnexpectedEOF"}
+
+	a := NewState()
+	a.Var(err).SetUsed()
+	a.Var(err).SetNotNil(true)
+	a.Var(err).SetTakenCare(false, token.NoPos)
+	a.Var(err).SetClass(eof, true)
+	a.Var(err).SetClass(unexpected, false)
+	a.Var(only).SetNotNil(true)
+	a.Derive(err, src)
+
+	b := NewState()
+	b.Var(err).SetUsed()
+	b.Var(err).SetNotNil(true)
+	b.Var(err).SetTakenCare(true, token.NoPos)
+	b.Var(err).SetClass(eof, false)
+	b.Derive(err, src)
+	b.Derive(err, other)
+
+	j := a.Join(b)
+
+	if unused := j.Unused(); len(unused) != 1 || unused[0] != only {
+		t.Errorf("errors unused on one side only must stay unused, got %v", unused)
+	}
+	if f := j.Var(only); f.IsNotNil() != nil || f.IsUsed() {
+		t.Error("facts of errors known on one side only must be dropped")
+	}
+
+	facts := j.Var(err)
+	if v := facts.IsNotNil(); v == nil || !*v {
+		t.Error("agreed nil-ness must be kept")
+	}
+	if facts.IsTakenCare() {
+		t.Error("disagreed taken care status must become unknown")
+	}
+	if !facts.IsClassOf(eof) || facts.Is(eof) {
+		t.Error("class must be kept as non-exact")
+	}
+	if facts.IsClassOf(unexpected) {
+		t.Error("class known on one side only must be dropped")
+	}
+	if origins := j.Origins(err); len(origins) != 2 || origins[1] != src {
+		t.Errorf("unexpected origins %v", origins)
+	}
+
+	if !j.Join(j).Equal(j) {
+		t.Error("join must be idempotent")
+	}
+	if !a.Join(b).Equal(b.Join(a)) {
+		t.Error("join must be commutative")
+	}
+}
@@ -0,0 +1,218 @@
+package tracing
+
+import (
+	"fmt"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/ssa"
+
+	"github.com/sirkon/cerrful/internal/cir"
+)
+
+// Summary describes errors a function returns as seen by its callers. Summaries are exported
+// as [golang.org/x/tools/go/analysis.Fact], so rules can rely on them in importing packages.
+//
+// Only returns of errors are summarized, success returns are not modelled.
+type Summary struct {
+	// Created is set if the function returns errors it created: constructor calls, sentinels,
+	// typed errors and joins.
+	Created bool
+
+	// Wrapped is set if the function returns annotated errors.
+	Wrapped bool
+
+	// Passed lists indexes of error parameters the function returns as is. Receivers of methods
+	// are counted as parameters.
+	Passed []int
+
+	// Propagated is set if the function returns errors of other calls as is.
+	Propagated bool
+
+	// Sentinels and Types are exact sentinel values and error types the function can return.
+	Sentinels []cir.Reference
+	Types     []cir.Reference
+
+	// Logged is set if errors returned are logged by the function on every path before
+	// they are returned.
+	Logged bool
+}
+
+// AFact makes summaries analysis facts.
+func (*Summary) AFact() {}
+
+func (s *Summary) String() string {
+	var parts []string
+	if s.Created {
+		parts = append(parts, "created")
+	}
+	if s.Wrapped {
+		parts = append(parts, "wrapped")
+	}
+	for _, i := range s.Passed {
+		parts = append(parts, fmt.Sprintf("passed(%d)", i))
+	}
+	if s.Propagated {
+		parts = append(parts, "propagated")
+	}
+	for _, ref := range s.Sentinels {
+		parts = append(parts, "sentinel("+refText(ref)+")")
+	}
+	for _, ref := range s.Types {
+		parts = append(parts, "type("+refText(ref)+")")
+	}
+	if s.Logged {
+		parts = append(parts, "logged")
+	}
+
+	return "errors: " + strings.Join(parts, " ")
+}
+
+// Transparent checks if the function returns nothing but the error parameter as is, like
+//
+//	func check(err error) error {
+//	    return err
+//	}
+//
+// Returns the index of the parameter.
+func (s *Summary) Transparent() (int, bool) {
+	if len(s.Passed) != 1 || s.Created || s.Wrapped || s.Propagated {
+		return 0, false
+	}
+
+	return s.Passed[0], true
+}
+
+// Fresh checks if the function returns no errors as is, each of them is either created or
+// annotated, like
+//
+//	func (d *decoder) errorf(format string, a ...any) error {
+//	    return fmt.Errorf("line %d: %w", d.line, fmt.Errorf(format, a...))
+//	}
+func (s *Summary) Fresh() bool {
+	return (s.Created || s.Wrapped) && !s.Propagated && len(s.Passed) == 0
+}
+
+// Summaries look up summaries of functions of other packages. They return nil if there is
+// no summary of the function.
+type Summaries func(fn *types.Func) *Summary
+
+// Environment describes what is known beyond the function interpreted. Any of its fields
+// can be nil.
+type Environment struct {
+	// Summaries of functions of other packages called.
+	Summaries Summaries
+
+	// Local summaries of functions of the package analyzed, see [InterpretPackage].
+	Local Summaries
+
+	// Layer returns the name of the layer the package belongs to. Errors of calls of other
+	// layers must be annotated before they are returned. Every package is a layer of its
+	// own if it is not set.
+	Layer func(pkg *types.Package) string
+
+	// Sentinel checks if the package-level error variable is configured to be a sentinel.
+	// Other variables can be sentinels too, see [tracer.sentinelRef].
+	Sentinel func(ref Reference) bool
+
+	// constants are package-level error variables of the package analyzed initialized with
+	// constructors and never assigned after, see [InterpretPackage].
+	constants map[*ssa.Global]bool
+}
+
+// summarize builds the summary of the function from facts of errors returned on all paths.
+// Returns nil if the function returns no errors.
+func (t *tracer) summarize(finals []*State) *Summary {
+	res := &Summary{Logged: t.loggedExits && !t.unloggedExits}
+	var returns bool
+	for _, state := range finals {
+		for _, facts := range state.Exits() {
+			if notNil := facts.IsNotNil(); notNil != nil && !*notNil {
+				continue
+			}
+			returns = true
+
+			switch {
+			case facts.IsCreated():
+				res.Created = true
+			case facts.IsWrapped():
+				res.Wrapped = true
+			case facts.IsBare():
+				if i := t.param(facts.Origin()); i >= 0 {
+					if !slices.Contains(res.Passed, i) {
+						res.Passed = append(res.Passed, i)
+					}
+				} else {
+					res.Propagated = true
+				}
+			}
+
+			for class, exact := range facts.classOf {
+				if !exact {
+					continue
+				}
+
+				if t.isSentinel(class) {
+					res.Sentinels = appendRef(res.Sentinels, class)
+				} else {
+					res.Types = appendRef(res.Types, class)
+				}
+			}
+		}
+	}
+	if !returns {
+		return nil
+	}
+
+	slices.Sort(res.Passed)
+	slices.SortFunc(res.Sentinels, compareRefs)
+	slices.SortFunc(res.Types, compareRefs)
+
+	return res
+}
+
+// param returns the index of the parameter declared at the given position, -1 if there is none.
+func (t *tracer) param(pos token.Pos) int {
+	for i, p := range t.fn.Params {
+		if pos == p.Pos() {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// isSentinel checks if the class refers to a package-level variable rather than a type.
+func (t *tracer) isSentinel(class cir.Reference) bool {
+	if class.Type != "" || t.fn.Pkg == nil {
+		return false
+	}
+
+	pkg := lookupPackage(t.fn.Pkg.Pkg, class.Package)
+	if pkg == nil {
+		return false
+	}
+
+	_, ok := pkg.Scope().Lookup(class.Name).(*types.Var)
+	return ok
+}
+
+func appendRef(refs []cir.Reference, ref cir.Reference) []cir.Reference {
+	if slices.Contains(refs, ref) {
+		return refs
+	}
+
+	return append(refs, ref)
+}
+
+func compareRefs(a, b cir.Reference) int {
+	if c := strings.Compare(a.Package, b.Package); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Type, b.Type); c != 0 {
+		return c
+	}
+	return strings.Compare(a.Name, b.Name)
+}
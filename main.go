@@ -1,5 +1,11 @@
 package main
 
-func main() {
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/sirkon/cerrful/analyzer"
+)
 
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
 }
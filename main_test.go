@@ -4,33 +4,20 @@ import (
 	"embed"
 	_ "embed"
 	"fmt"
-	"reflect"
 	"strings"
 	"testing"
 
-	"github.com/sirkon/deepequal"
-
 	"github.com/sirkon/cerrful/internal/cerrful"
+	"github.com/sirkon/cerrful/internal/cir"
 )
 
 //go:embed testdata
 var cirTestCases embed.FS
 
 func TestCIR(t *testing.T) {
-	expected := map[string]*cerrful.CIRFunction{
-		// "case_alias_error": {
-		// 	Name: "aliasError",
-		// 	Nodes: []cerrful.Node{
-		// 		cerrful.Assign{
-		// 			Name:    "",
-		// 			RHS:     "",
-		// 			Flavor:  "",
-		// 			IsCtor:  false,
-		// 			CtorMsg: "",
-		// 			CtorVia: "",
-		// 		},
-		// 	},
-		// },
+	misc, err := cirTestCases.ReadFile("testdata/cases/misc.go")
+	if err != nil {
+		t.Fatal(fmt.Errorf("read shared declarations: %w", err))
 	}
 
 	files, err := cirTestCases.ReadDir("testdata/cases")
@@ -43,7 +30,7 @@ func TestCIR(t *testing.T) {
 			continue
 		}
 
-		if !strings.HasPrefix(file.Name(), "case_") {
+		if !strings.HasPrefix(file.Name(), "case_") || !strings.HasSuffix(file.Name(), ".go") {
 			continue
 		}
 
@@ -53,30 +40,45 @@ func TestCIR(t *testing.T) {
 				t.Fatalf("read file %s: %s", file.Name(), err)
 			}
 
-			got, err := cerrful.DemoTranslate(string(code))
+			got, err := cerrful.DemoTranslate(string(code), string(misc))
 			if err != nil {
-				t.Fatal("get cir for the case file")
+				t.Fatal(fmt.Errorf("get cir for the case file: %w", err))
 			}
 
 			t.Log("\n" + got.Pretty(true))
+			if len(got.Functions) == 0 {
+				t.Fatal("no functions translated")
+			}
 
-			expectedCIR, ok := expected[file.Name()]
-			if !ok {
-				t.Fatal("no cir found for", file.Name())
+			golden := strings.TrimSuffix(file.Name(), ".go") + ".cir"
+			text, err := cirTestCases.ReadFile("testdata/cases/" + golden)
+			if err != nil {
+				t.Fatalf("read expected cir %s: %s", golden, err)
+			}
+			expected, err := cerrful.ParsePretty(got.Package, string(text))
+			if err != nil {
+				t.Fatalf("parse expected cir %s: %s", golden, err)
+			}
+
+			// Positions are not a part of the text form, they are checked to be set only.
+			for _, fn := range got.Functions {
+				for _, node := range fn.Nodes {
+					cir.Inspect(node, func(n cir.Node) bool {
+						if !n.Pos().IsValid() || n.End() < n.Pos() {
+							t.Errorf("%T has no valid span", n)
+						}
+						return true
+					})
+				}
 			}
 
-			if !reflect.DeepEqual(expectedCIR, code) {
-				deepequal.SideBySide(
-					t,
-					"cir",
-					&cerrful.CIRProgram{
-						File:      file.Name(),
-						Functions: []cerrful.CIRFunction{*expectedCIR},
-					},
-					got,
-				)
+			for _, v := range cir.Validate(got.Functions...) {
+				t.Errorf("%s: %s", got.Position(v.Node), v)
 			}
 
+			for _, diff := range cerrful.Diff(expected, got) {
+				t.Error(diff)
+			}
 		})
 	}
 }
@@ -5,7 +5,8 @@ import (
 	"io"
 )
 
-func logError() {
+func logError() error {
 	err := fmt.Errorf("read stream: %w", io.EOF)
 	fmt.Println("fetch data:", err)
+	return nil
 }
\ No newline at end of file
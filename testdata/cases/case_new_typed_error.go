@@ -0,0 +1,10 @@
+package main
+
+import "io/fs"
+
+func newTypedError(path string) error {
+	if path == "" {
+		return &fs.PathError{Op: "open", Path: path, Err: fs.ErrInvalid}
+	}
+	return codeError(404)
+}
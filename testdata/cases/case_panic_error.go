@@ -2,9 +2,11 @@ package main
 
 import "os"
 
-func panicError() {
+func panicError() error {
 	_, err := os.UserHomeDir()
 	if err != nil {
 		panic(err)
 	}
+
+	return nil
 }
\ No newline at end of file
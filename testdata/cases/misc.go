@@ -10,4 +10,9 @@ func validateConfig(cfg any) error {
 	return nil
 }
 
-type appConfig struct{}
\ No newline at end of file
+type appConfig struct{}
+type codeError int
+
+func (e codeError) Error() string {
+	return "code error"
+}